@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ASCII ART TEMPLATES, FIVE LINES EACH, IN THE SPIRIT OF wttr.in
+var asciiArt = map[string][]string{
+	"Clear": {
+		`    \   /    `,
+		`     .-.     `,
+		`  - (   ) -  `,
+		`     '-'     `,
+		`    /   \    `,
+	},
+	"Clouds": {
+		`             `,
+		`     .--.    `,
+		`  .-(    ).  `,
+		` (___.__)__) `,
+		`             `,
+	},
+	"Rain": {
+		`     .-.     `,
+		`    (   ).   `,
+		`   (___(__)  `,
+		`    ' ' ' '  `,
+		`   ' ' ' '   `,
+	},
+	"Snow": {
+		`     .-.     `,
+		`    (   ).   `,
+		`   (___(__)  `,
+		`    *  *  *  `,
+		`   *  *  *   `,
+	},
+	"Thunderstorm": {
+		`     .-.     `,
+		`    (   ).   `,
+		`   (___(__)  `,
+		`    /_ /_    `,
+		`     /  /    `,
+	},
+	"Mist": {
+		`             `,
+		` _ - _ - _ - `,
+		`  _ - _ - _  `,
+		` _ - _ - _ - `,
+		`             `,
+	},
+}
+
+// artFor maps related conditions onto the templates above
+func artFor(condition string) []string {
+	switch condition {
+	case "Drizzle":
+		condition = "Rain"
+	case "Fog", "Haze", "Smoke", "Dust", "Sand", "Ash":
+		condition = "Mist"
+	case "Squall", "Tornado":
+		condition = "Thunderstorm"
+	}
+
+	if art, ok := asciiArt[condition]; ok {
+		return art
+	}
+	return asciiArt["Clouds"]
+}
+
+// printASCII writes the condition art with the location, temperature and
+// condition alongside it
+func printASCII(w io.Writer, weather WeatherData) {
+	info := []string{
+		"",
+		weather.Location,
+		formatTemperature(weather.Temperature, weather.Unit),
+		conditionText(weather),
+		"",
+	}
+
+	for i, line := range artFor(weather.Condition) {
+		fmt.Fprintln(w, strings.TrimRight(line+"  "+info[i], " "))
+	}
+}
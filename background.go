@@ -0,0 +1,78 @@
+package main
+
+import (
+	"time"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// BACKGROUND_FADE is how long the tint takes to reach a new condition
+const BACKGROUND_FADE = time.Second
+
+// conditionBackgrounds tints the window by condition. Add a row to give a new
+// condition its own tint; anything missing uses the theme background.
+var conditionBackgrounds = map[string]rl.Color{
+	"Clear":        rl.NewColor(255, 244, 214, 255),
+	"Clouds":       rl.NewColor(226, 230, 236, 255),
+	"Drizzle":      rl.NewColor(214, 228, 240, 255),
+	"Rain":         rl.NewColor(200, 216, 234, 255),
+	"Thunderstorm": rl.NewColor(196, 194, 216, 255),
+	"Snow":         rl.NewColor(240, 246, 252, 255),
+	"Mist":         rl.NewColor(232, 234, 234, 255),
+	"Fog":          rl.NewColor(232, 234, 234, 255),
+}
+
+// DARK_TINT_STRENGTH IS HOW FAR A DARK BACKGROUND MOVES TOWARD THE PALE TINT
+const DARK_TINT_STRENGTH float32 = 0.12
+
+// conditionBackground returns the tint for condition, or the theme background
+// when there is no data or the condition has no tint. Dark themes get only a
+// hint of the tint so text stays readable.
+func conditionBackground(condition string, theme Theme) rl.Color {
+	color, ok := conditionBackgrounds[condition]
+	if !ok {
+		return theme.Background
+	}
+	if theme.Dark {
+		return lerpColor(theme.Background, color, DARK_TINT_STRENGTH)
+	}
+	return color
+}
+
+// lerpColor moves each channel of from a fraction t of the way to to
+func lerpColor(from, to rl.Color, t float32) rl.Color {
+	channel := func(a, b uint8) uint8 {
+		return uint8(rl.Lerp(float32(a), float32(b), t) + 0.5)
+	}
+	return rl.NewColor(channel(from.R, to.R), channel(from.G, to.G), channel(from.B, to.B), channel(from.A, to.A))
+}
+
+// backgroundFade blends the window background from one tint to the next
+// over BACKGROUND_FADE, timed by the clock so it is the same at any FPS
+type backgroundFade struct {
+	from, to rl.Color
+	start    time.Time
+}
+
+func newBackgroundFade(color rl.Color) *backgroundFade {
+	return &backgroundFade{from: color, to: color}
+}
+
+// Color returns the blended background at now
+func (f *backgroundFade) Color(now time.Time) rl.Color {
+	t := float32(now.Sub(f.start)) / float32(BACKGROUND_FADE)
+	return lerpColor(f.from, f.to, min(1, t))
+}
+
+// Fading reports whether a fade is still under way at now
+func (f *backgroundFade) Fading(now time.Time) bool {
+	return f.from != f.to && now.Sub(f.start) < BACKGROUND_FADE
+}
+
+// Target starts a fade from the current color toward color, if it changed
+func (f *backgroundFade) Target(color rl.Color, now time.Time) {
+	if color == f.to {
+		return
+	}
+	f.from, f.to, f.start = f.Color(now), color, now
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"errors"
+)
+
+// cityResult is everything fetched for one city in one go. The UI applies it
+// as a unit, so the current and forecast panels never show data from
+// different requests.
+type cityResult struct {
+	city         string
+	withForecast bool
+	weather      WeatherData
+	forecast     []ForecastEntry
+	err          error
+}
+
+// fetchCity fetches current weather, and the forecast when asked, in parallel.
+// Both halves report over one channel and the result is all or nothing: if
+// either fails, neither is returned.
+func fetchCity(ctx context.Context, provider Provider, city string, withForecast bool) cityResult {
+	return fetchCityWith(city, withForecast,
+		func(city string) (WeatherData, error) { return provider.Fetch(ctx, city) },
+		func(city string) ([]ForecastEntry, error) { return provider.Forecast(ctx, city) },
+	)
+}
+
+// fetchCityWith is fetchCity with the fetchers injected, so the channel
+// handling can be driven concurrently without the GUI or the network
+func fetchCityWith(
+	city string,
+	withForecast bool,
+	fetchWeather func(city string) (WeatherData, error),
+	fetchForecast func(city string) ([]ForecastEntry, error),
+) cityResult {
+	type part struct {
+		weather  *WeatherData
+		forecast []ForecastEntry
+		err      error
+	}
+
+	pending := 1
+	if withForecast {
+		pending = 2
+	}
+	parts := make(chan part, pending)
+
+	go func() {
+		weather, err := fetchWeather(city)
+		parts <- part{weather: &weather, err: err}
+	}()
+
+	if withForecast {
+		go func() {
+			forecast, err := fetchForecast(city)
+			parts <- part{forecast: forecast, err: err}
+		}()
+	}
+
+	result := cityResult{city: city, withForecast: withForecast}
+	var errs []error
+	for range pending {
+		p := <-parts
+		if p.err != nil {
+			errs = append(errs, p.err)
+			continue
+		}
+		if p.weather != nil {
+			result.weather = *p.weather
+		} else {
+			result.forecast = p.forecast
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return cityResult{city: city, withForecast: withForecast, err: err}
+	}
+	return result
+}
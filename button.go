@@ -0,0 +1,50 @@
+package main
+
+import rl "github.com/gen2brain/raylib-go/raylib"
+
+// BUTTON_HOVER_BRIGHTNESS lightens a button while the mouse is over it
+const (
+	BUTTON_HOVER_BRIGHTNESS float32 = 0.3
+	COOLDOWN_BAR_HEIGHT     float32 = 4
+)
+
+// buttonClicked reports whether the left mouse button was pressed on bounds
+// this frame
+func buttonClicked(bounds rl.Rectangle) bool {
+	return rl.IsMouseButtonPressed(rl.MouseLeftButton) &&
+		rl.CheckCollisionPointRec(rl.GetMousePosition(), bounds)
+}
+
+// drawButton draws a labelled button, lightened on hover and greyed out
+// while disabled
+func drawButton(font rl.Font, theme Theme, bounds rl.Rectangle, label string, enabled bool) {
+	fill, text := theme.Accent, theme.Background
+	if !enabled {
+		fill, text = theme.InputBox, theme.Muted
+	} else if rl.CheckCollisionPointRec(rl.GetMousePosition(), bounds) {
+		fill = rl.ColorBrightness(fill, BUTTON_HOVER_BRIGHTNESS)
+	}
+
+	rl.DrawRectangleRec(bounds, fill)
+	rl.DrawRectangleLinesEx(bounds, 1, theme.Text)
+
+	size := rl.MeasureTextEx(font, label, 20, 0)
+	rl.DrawTextEx(
+		font,
+		label,
+		rl.NewVector2(bounds.X+(bounds.Width-size.X)/2, bounds.Y+(bounds.Height-size.Y)/2),
+		20, 0, text,
+	)
+}
+
+// drawCooldownBar draws a thin bar under bounds that shrinks as the search
+// cooldown runs out. remaining is the share left, as from cooldownRemaining.
+func drawCooldownBar(theme Theme, bounds rl.Rectangle, remaining float32) {
+	if remaining <= 0 {
+		return
+	}
+	bar := rl.NewRectangle(bounds.X, bounds.Y+bounds.Height+2, bounds.Width, COOLDOWN_BAR_HEIGHT)
+	rl.DrawRectangleRec(bar, theme.InputBox)
+	bar.Width *= remaining
+	rl.DrawRectangleRec(bar, theme.Accent)
+}
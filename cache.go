@@ -0,0 +1,152 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const CACHE_TTL_DEFAULT = 5 * time.Minute
+
+// cacheTTL is how long a lookup is served from the cache before the network
+// is asked again. It is read from CACHE_TTL in init once .env is loaded.
+var cacheTTL = CACHE_TTL_DEFAULT
+
+// Cache stores weather lookups for a limited time. Implementations must be
+// safe for concurrent use, since background fetches write to them.
+type Cache interface {
+	Get(city string) (WeatherData, bool)
+	Set(city string, weather WeatherData, ttl time.Duration)
+	// Peek is Get without the expiry check, for offline mode
+	Peek(city string) (WeatherData, bool)
+}
+
+type cacheEntry struct {
+	Weather   WeatherData
+	ExpiresAt time.Time
+}
+
+func normalizeCity(city string) string {
+	return strings.ToLower(strings.TrimSpace(city))
+}
+
+// newCache builds the backend named by the CACHE_BACKEND config value
+func newCache(backend string) (Cache, error) {
+	switch backend {
+	case "", "memory":
+		return newMemoryCache(), nil
+	case "disk":
+		dir, err := os.UserCacheDir()
+		if err != nil {
+			return nil, fmt.Errorf("failed to locate cache dir: %v", err)
+		}
+		return newDiskCache(filepath.Join(dir, "go-weather", "weather"))
+	default:
+		return nil, fmt.Errorf("unknown cache backend %q", backend)
+	}
+}
+
+// memoryCache keeps entries in a map for the life of the process
+type memoryCache struct {
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+func newMemoryCache() *memoryCache {
+	return &memoryCache{entries: make(map[string]cacheEntry)}
+}
+
+func (c *memoryCache) Get(city string) (WeatherData, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[normalizeCity(city)]
+	if !ok || time.Now().After(entry.ExpiresAt) {
+		return WeatherData{}, false
+	}
+	return entry.Weather, true
+}
+
+func (c *memoryCache) Peek(city string) (WeatherData, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[normalizeCity(city)]
+	return entry.Weather, ok
+}
+
+func (c *memoryCache) Set(city string, weather WeatherData, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[normalizeCity(city)] = cacheEntry{Weather: weather, ExpiresAt: time.Now().Add(ttl)}
+}
+
+// diskCache keeps one JSON file per city so entries survive restarts
+type diskCache struct {
+	mu  sync.Mutex
+	dir string
+}
+
+func newDiskCache(dir string) (*diskCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create cache dir: %v", err)
+	}
+	return &diskCache{dir: dir}, nil
+}
+
+// path hex-encodes the key so any city name makes a valid file name
+func (c *diskCache) path(city string) string {
+	return filepath.Join(c.dir, hex.EncodeToString([]byte(normalizeCity(city)))+".json")
+}
+
+func (c *diskCache) Get(city string) (WeatherData, bool) {
+	entry, ok := c.read(city)
+	if !ok || time.Now().After(entry.ExpiresAt) {
+		return WeatherData{}, false
+	}
+	return entry.Weather, true
+}
+
+func (c *diskCache) Peek(city string) (WeatherData, bool) {
+	entry, ok := c.read(city)
+	return entry.Weather, ok
+}
+
+// read loads the entry for city whether or not it has expired
+func (c *diskCache) read(city string) (cacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data, err := os.ReadFile(c.path(city))
+	if err != nil {
+		return cacheEntry{}, false
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return cacheEntry{}, false
+	}
+	return entry, true
+}
+
+func (c *diskCache) Set(city string, weather WeatherData, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// A FAILED WRITE ONLY COSTS A REFETCH LATER, SO IT IS LOGGED, NOT RETURNED
+	data, err := json.Marshal(cacheEntry{Weather: weather, ExpiresAt: time.Now().Add(ttl)})
+	if err != nil {
+		slog.Warn("Cache entry not encoded", "city", city, "err", err)
+		return
+	}
+	if err := os.WriteFile(c.path(city), data, 0o644); err != nil {
+		slog.Warn("Cache entry not written", "city", city, "err", err)
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewCacheBackends(t *testing.T) {
+	for _, backend := range []string{"", "memory"} {
+		if _, err := newCache(backend); err != nil {
+			t.Errorf("newCache(%q) = %v", backend, err)
+		}
+	}
+	if _, err := newCache("redis"); err == nil {
+		t.Error("newCache(\"redis\") succeeded, want an unknown backend error")
+	}
+}
+
+func TestDiskCacheSurvivesRestart(t *testing.T) {
+	dir := t.TempDir()
+
+	first, err := newDiskCache(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first.Set("São Paulo", WeatherData{Location: "São Paulo", Temperature: 24}, time.Minute)
+
+	second, err := newDiskCache(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	weather, ok := second.Get(" são paulo ")
+	if !ok || weather.Temperature != 24 {
+		t.Errorf("Get after restart = %+v, %v; want the stored entry", weather, ok)
+	}
+}
+
+func TestCacheHitMissExpiry(t *testing.T) {
+	disk, err := newDiskCache(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	backends := map[string]Cache{"memory": newMemoryCache(), "disk": disk}
+
+	for name, cache := range backends {
+		t.Run(name, func(t *testing.T) {
+			if _, ok := cache.Get("Paris"); ok {
+				t.Error("empty cache: Get hit, want a miss")
+			}
+
+			cache.Set("Paris", WeatherData{Location: "Paris", Temperature: 18}, time.Minute)
+			if weather, ok := cache.Get("  PARIS "); !ok || weather.Temperature != 18 {
+				t.Errorf("fresh entry: Get = %+v, %v; want a hit on the normalized name", weather, ok)
+			}
+			if _, ok := cache.Get("Lyon"); ok {
+				t.Error("other city: Get hit, want a miss")
+			}
+
+			// AN EXPIRED ENTRY MISSES, BUT OFFLINE MODE CAN STILL PEEK AT IT
+			cache.Set("Oslo", WeatherData{Location: "Oslo", Temperature: -3}, -time.Second)
+			if _, ok := cache.Get("Oslo"); ok {
+				t.Error("expired entry: Get hit, want a miss")
+			}
+			if weather, ok := cache.Peek("Oslo"); !ok || weather.Temperature != -3 {
+				t.Errorf("expired entry: Peek = %+v, %v; want the stale entry", weather, ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// printWeather writes weather as plain text for the headless -no-gui mode
+func printWeather(w io.Writer, weather WeatherData) {
+	fmt.Fprintf(w, "%s\n", weather.Location)
+	fmt.Fprintf(w, "  Temperature: %s (feels like %s)\n",
+		formatTemperature(weather.Temperature, weather.Unit), formatTemperature(weather.FeelsLike, weather.Unit))
+	fmt.Fprintf(w, "  Condition:   %s\n", conditionText(weather))
+	fmt.Fprintf(w, "  Humidity:    %d%%\n", weather.Humidity)
+	fmt.Fprintf(w, "  Wind:        %s\n", strings.TrimSpace(formatWind(weather.WindSpeed, weather.Unit)+" "+windCardinal(weather.WindDeg)))
+	fmt.Fprintf(w, "  Pressure:    %d hPa\n", weather.Pressure)
+	fmt.Fprintf(w, "  Visibility:  %s\n", formatVisibility(weather.Visibility))
+	if !weather.Sunrise.IsZero() && !weather.Sunset.IsZero() {
+		fmt.Fprintf(w, "  Sunrise:     %s\n", formatTime(weather.Sunrise))
+		fmt.Fprintf(w, "  Sunset:      %s\n", formatTime(weather.Sunset))
+	}
+}
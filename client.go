@@ -0,0 +1,201 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// CONNECTION REUSE DEFAULTS. EVERY REQUEST GOES TO THE SAME API HOST, SO
+// KEEPING A FEW IDLE CONNECTIONS SAVES A TLS HANDSHAKE PER FETCH.
+const (
+	HTTP_MAX_IDLE_CONNS_DEFAULT int = 10
+	HTTP_IDLE_TIMEOUT_DEFAULT       = 90 * time.Second
+	API_TIMEOUT_DEFAULT             = 10 * time.Second
+)
+
+// RETRY POLICY FOR 5XX RESPONSES AND NETWORK ERRORS. THE DELAY DOUBLES FROM
+// RETRY_BASE_DELAY EACH ATTEMPT, PLUS UP TO 50% RANDOM JITTER.
+const (
+	API_RETRIES_DEFAULT int = 3
+	RETRY_BASE_DELAY        = 500 * time.Millisecond
+)
+
+// retryAttempts is the total number of tries per request, read from
+// API_RETRIES in init
+var retryAttempts = API_RETRIES_DEFAULT
+
+// retryError is returned once every attempt has failed
+type retryError struct {
+	attempts int
+	err      error
+}
+
+func (e *retryError) Error() string {
+	return fmt.Sprintf("%v (after %d attempts)", e.err, e.attempts)
+}
+
+func (e *retryError) Unwrap() error {
+	return e.err
+}
+
+// httpClient is shared by every API call so connections are reused. It is
+// built in init once .env is loaded, since the tuning comes from config.
+var httpClient *http.Client
+
+func newHTTPClient() *http.Client {
+	maxIdle := envInt("HTTP_MAX_IDLE_CONNS", HTTP_MAX_IDLE_CONNS_DEFAULT)
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConns = maxIdle
+	transport.MaxIdleConnsPerHost = maxIdle
+	transport.IdleConnTimeout = envDuration("HTTP_IDLE_TIMEOUT", HTTP_IDLE_TIMEOUT_DEFAULT)
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   envDuration("API_TIMEOUT", API_TIMEOUT_DEFAULT),
+	}
+}
+
+// withQuery adds params to the query endpoint already has. API_URL may carry
+// parameters of its own, such as mode=, which appending "?" would break.
+func withQuery(endpoint string, params url.Values) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("invalid API URL %q: %v", endpoint, err)
+	}
+
+	query := u.Query()
+	for key, values := range params {
+		query[key] = values
+	}
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// retryGet issues a GET bound to ctx, so cancelling ctx aborts the request or
+// the wait between retries. Connection errors and 5xx responses are retried
+// with backoff. A timeout is not: it has already cost a full API_TIMEOUT, and
+// retrying would multiply the wait. Any other response, including 4xx, is
+// returned to the caller as is.
+func retryGet(ctx context.Context, client *http.Client, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= retryAttempts; attempt++ {
+		start := time.Now()
+		resp, err := client.Do(req)
+		logRequest(url, attempt, resp, err, time.Since(start))
+		if err == nil && resp.StatusCode < http.StatusInternalServerError {
+			return resp, nil
+		}
+
+		if err != nil {
+			if ctx.Err() != nil || isTimeout(err) {
+				return nil, err
+			}
+			lastErr = err
+		} else {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+			lastErr = fmt.Errorf("API error (%d)", resp.StatusCode)
+		}
+
+		if attempt == retryAttempts {
+			break
+		}
+
+		select {
+		case <-time.After(retryDelay(attempt)):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	return nil, &retryError{attempts: retryAttempts, err: lastErr}
+}
+
+// logRequest records one attempt. Failures and error statuses are warnings,
+// anything else is info.
+func logRequest(url string, attempt int, resp *http.Response, err error, latency time.Duration) {
+	switch {
+	case err != nil:
+		slog.Warn("API request failed", "url", url, "attempt", attempt, "latency", latency, "err", err)
+	case resp.StatusCode >= http.StatusBadRequest:
+		slog.Warn("API request", "url", url, "attempt", attempt, "status", resp.StatusCode, "latency", latency)
+	default:
+		slog.Info("API request", "url", url, "attempt", attempt, "status", resp.StatusCode, "latency", latency)
+	}
+}
+
+// retryDelay is the backoff before the attempt after attempt
+func retryDelay(attempt int) time.Duration {
+	delay := RETRY_BASE_DELAY << (attempt - 1)
+	return delay + time.Duration(rand.Int63n(int64(delay)/2+1))
+}
+
+// isTimeout reports whether err is a deadline or a network timeout
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout())
+}
+
+// requestError reports timeouts plainly and wraps anything else with what
+// was being done
+func requestError(action string, err error) error {
+	if isTimeout(err) {
+		return fmt.Errorf("request timed out")
+	}
+	return fmt.Errorf("failed to %s: %w", action, err)
+}
+
+// RATE_LIMIT_WAIT_DEFAULT is how long to back off after a 429 that does not
+// say how long to wait
+const RATE_LIMIT_WAIT_DEFAULT = time.Minute
+
+// RateLimitError is returned for HTTP 429. RetryAfter is zero when the
+// response had no usable Retry-After header.
+type RateLimitError struct {
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("rate limited - try again in %ds", int(math.Ceil(e.RetryAfter.Seconds())))
+	}
+	return "rate limited - try again later"
+}
+
+// Wait is how long to hold off before calling the API again
+func (e *RateLimitError) Wait() time.Duration {
+	if e.RetryAfter > 0 {
+		return e.RetryAfter
+	}
+	return RATE_LIMIT_WAIT_DEFAULT
+}
+
+// rateLimitError builds the error for a 429 response from its Retry-After
+// header, which is either a number of seconds or an HTTP date
+func rateLimitError(resp *http.Response, now time.Time) *RateLimitError {
+	value := resp.Header.Get("Retry-After")
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return &RateLimitError{RetryAfter: time.Duration(seconds) * time.Second}
+	}
+	if at, err := http.ParseTime(value); err == nil && at.After(now) {
+		return &RateLimitError{RetryAfter: at.Sub(now)}
+	}
+	return &RateLimitError{}
+}
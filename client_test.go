@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithQuery(t *testing.T) {
+	params := url.Values{"appid": {"KEY"}, "q": {"São Paulo"}}
+	tests := []struct {
+		endpoint, want string
+	}{
+		{"https://api.example.com/data/2.5/weather", "https://api.example.com/data/2.5/weather?appid=KEY&q=S%C3%A3o+Paulo"},
+		{"https://api.example.com/data/2.5/weather?mode=xml", "https://api.example.com/data/2.5/weather?appid=KEY&mode=xml&q=S%C3%A3o+Paulo"},
+		{"https://api.example.com/weather?q=Paris", "https://api.example.com/weather?appid=KEY&q=S%C3%A3o+Paulo"},
+		{"http://localhost:8080/weather?", "http://localhost:8080/weather?appid=KEY&q=S%C3%A3o+Paulo"},
+	}
+	for _, test := range tests {
+		got, err := withQuery(test.endpoint, params)
+		if err != nil || got != test.want {
+			t.Errorf("withQuery(%q) = %q, %v; want %q", test.endpoint, got, err, test.want)
+		}
+	}
+
+	if _, err := withQuery("http://bad host/", params); err == nil {
+		t.Error("withQuery accepted a URL with a space in the host")
+	}
+}
+
+func TestHTTPClientReusesConnections(t *testing.T) {
+	var opened atomic.Int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			opened.Add(1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	client := newHTTPClient()
+	for range 5 {
+		resp, err := retryGet(context.Background(), client, server.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}
+
+	if n := opened.Load(); n != 1 {
+		t.Errorf("5 requests opened %d connections, want 1", n)
+	}
+}
+
+func TestRequestTimeoutIsFriendly(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer server.Close()
+	setRetryAttempts(t, API_RETRIES_DEFAULT)
+
+	client := &WeatherClient{BaseURL: server.URL, HTTPClient: &http.Client{Timeout: 50 * time.Millisecond}}
+	_, err := client.GetCurrent(context.Background(), "London")
+	if err == nil || err.Error() != "request timed out" {
+		t.Fatalf("err = %v, want \"request timed out\"", err)
+	}
+
+	// A TIMEOUT IS NOT RETRIED, SO IT COSTS ONE API_TIMEOUT, NOT THREE
+	if n := hits.Load(); n != 1 {
+		t.Errorf("timed out request was sent %d times, want 1", n)
+	}
+}
+
+// setRetryAttempts sets retryAttempts for one test
+func setRetryAttempts(t *testing.T, attempts int) {
+	saved := retryAttempts
+	retryAttempts = attempts
+	t.Cleanup(func() { retryAttempts = saved })
+}
+
+// serveStatuses answers with each status in turn, then the last one forever,
+// counting the requests
+func serveStatuses(t *testing.T, hits *atomic.Int32, statuses ...int) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(hits.Add(1))
+		w.WriteHeader(statuses[min(n, len(statuses))-1])
+		w.Write([]byte(`{"name": "London"}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestRetryGetRecoversFrom5xx(t *testing.T) {
+	setRetryAttempts(t, API_RETRIES_DEFAULT)
+
+	var hits atomic.Int32
+	server := serveStatuses(t, &hits, http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusOK)
+
+	resp, err := retryGet(context.Background(), server.Client(), server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || hits.Load() != 3 {
+		t.Errorf("status %d after %d attempts, want 200 after 3", resp.StatusCode, hits.Load())
+	}
+}
+
+func TestRetryGetGivesUp(t *testing.T) {
+	setRetryAttempts(t, 2)
+
+	var hits atomic.Int32
+	server := serveStatuses(t, &hits, http.StatusBadGateway)
+
+	_, err := retryGet(context.Background(), server.Client(), server.URL)
+	if err == nil || !strings.Contains(err.Error(), "(after 2 attempts)") {
+		t.Errorf("err = %v, want the attempt count", err)
+	}
+	if hits.Load() != 2 {
+		t.Errorf("sent %d times, want 2", hits.Load())
+	}
+}
+
+func TestRetryGetLeaves4xx(t *testing.T) {
+	setRetryAttempts(t, API_RETRIES_DEFAULT)
+
+	var hits atomic.Int32
+	server := serveStatuses(t, &hits, http.StatusNotFound)
+
+	resp, err := retryGet(context.Background(), server.Client(), server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNotFound || hits.Load() != 1 {
+		t.Errorf("status %d after %d attempts, want 404 after 1", resp.StatusCode, hits.Load())
+	}
+}
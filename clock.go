@@ -0,0 +1,38 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+)
+
+// CLOCK LAYOUTS FOR THE TIME_FORMAT SETTING
+const (
+	TIME_FORMAT_24H = "24h"
+	TIME_FORMAT_12H = "12h"
+)
+
+// clockLayout is the time.Format layout every clock time is drawn with, read
+// from TIME_FORMAT in init
+var clockLayout = "15:04"
+
+// envClockLayout reads TIME_FORMAT, "24h" (the default) or "12h"
+func envClockLayout() string {
+	switch value := strings.ToLower(strings.TrimSpace(os.Getenv("TIME_FORMAT"))); value {
+	case "", TIME_FORMAT_24H:
+		return "15:04"
+	case TIME_FORMAT_12H:
+		return "3:04 PM"
+	default:
+		slog.Warn("TIME_FORMAT not recognised, using 24h", "value", value)
+		return "15:04"
+	}
+}
+
+// formatTime renders t as a clock time in its own location. Times for a city
+// come from cityLocalTime or unixLocalTime, so they are already in the
+// city's zone; times on the local machine are in the machine's zone.
+func formatTime(t time.Time) string {
+	return t.Format(clockLayout)
+}
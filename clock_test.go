@@ -0,0 +1,53 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEnvClockLayout(t *testing.T) {
+	tests := []struct {
+		value, want string
+	}{
+		{"", "15:04"},
+		{"24h", "15:04"},
+		{" 12H ", "3:04 PM"},
+		{"am/pm", "15:04"},
+	}
+	for _, test := range tests {
+		t.Setenv("TIME_FORMAT", test.value)
+		if got := envClockLayout(); got != test.want {
+			t.Errorf("TIME_FORMAT=%q gave %q, want %q", test.value, got, test.want)
+		}
+	}
+}
+
+func TestFormatTime(t *testing.T) {
+	saved := clockLayout
+	t.Cleanup(func() { clockLayout = saved })
+
+	// 23:30 UTC, SO OFFSETS EITHER SIDE CROSS MIDNIGHT AND NOON
+	now := time.Date(2024, 3, 10, 23, 30, 0, 0, time.UTC)
+	tests := []struct {
+		format string
+		offset int
+		want   string
+	}{
+		{TIME_FORMAT_24H, 0, "23:30"},
+		{TIME_FORMAT_24H, 5*3600 + 1800, "05:00"},
+		{TIME_FORMAT_24H, -10 * 3600, "13:30"},
+		{TIME_FORMAT_24H, -12 * 3600, "11:30"},
+		{TIME_FORMAT_12H, 0, "11:30 PM"},
+		{TIME_FORMAT_12H, 5*3600 + 1800, "5:00 AM"},
+		{TIME_FORMAT_12H, -10 * 3600, "1:30 PM"},
+		{TIME_FORMAT_12H, -12 * 3600, "11:30 AM"},
+		{TIME_FORMAT_12H, 1800, "12:00 AM"},
+	}
+	for _, test := range tests {
+		t.Setenv("TIME_FORMAT", test.format)
+		clockLayout = envClockLayout()
+		if got := formatTime(cityLocalTime(now, test.offset)); got != test.want {
+			t.Errorf("%s at offset %ds = %q, want %q", test.format, test.offset, got, test.want)
+		}
+	}
+}
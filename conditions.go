@@ -0,0 +1,49 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// conditionLabels turns OpenWeather's terse "main" values into friendlier
+// display text. The raw value stays in WeatherData.Condition for icon and
+// color logic; only drawing goes through conditionLabel.
+var conditionLabels = map[string]string{
+	"Clear":        "Clear sky",
+	"Clouds":       "Cloudy",
+	"Drizzle":      "Light rain",
+	"Mist":         "Misty",
+	"Fog":          "Foggy",
+	"Haze":         "Hazy",
+	"Smoke":        "Smoky",
+	"Dust":         "Dusty",
+	"Squall":       "Gusty",
+	"Thunderstorm": "Thunderstorms",
+}
+
+// loadConditionLabels merges CONDITION_LABELS, e.g. "Mist=Foggy,Rain=Wet",
+// over the defaults. Malformed pairs are logged and skipped.
+func loadConditionLabels() {
+	for _, pair := range strings.Split(os.Getenv("CONDITION_LABELS"), ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+
+		condition, label, ok := strings.Cut(pair, "=")
+		condition, label = strings.TrimSpace(condition), strings.TrimSpace(label)
+		if !ok || condition == "" || label == "" {
+			slog.Warn("CONDITION_LABELS: skipping entry, expected Condition=Label", "entry", pair)
+			continue
+		}
+		conditionLabels[condition] = label
+	}
+}
+
+// conditionLabel returns the display text, passing unknown conditions through
+func conditionLabel(condition string) string {
+	if label, ok := conditionLabels[condition]; ok {
+		return label
+	}
+	return condition
+}
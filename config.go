@@ -0,0 +1,174 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const STALE_AFTER_DEFAULT = 30 * time.Minute
+
+// envDuration reads a positive duration such as "30m" from the environment.
+// An unset value gives fallback; an invalid one is logged and gives fallback.
+func envDuration(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		slog.Warn(key+" is not a positive duration, using the default", "value", value, "default", fallback)
+		return fallback
+	}
+	return duration
+}
+
+// envInt reads a positive integer from the environment, with the same
+// fallback rules as envDuration
+func envInt(key string, fallback int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil || number < 1 {
+		slog.Warn(key+" is not a positive number, using the default", "value", value, "default", fallback)
+		return fallback
+	}
+	return number
+}
+
+// CONFIG_FILE is looked for in the working directory, then in the user's
+// config dir (~/.config/go-weather on Linux)
+const (
+	CONFIG_FILE = "config.json"
+	CONFIG_DIR  = "go-weather"
+)
+
+// Config is the config.json alternative to .env. Environment variables
+// override the file field by field.
+type Config struct {
+	APIKey          string `json:"api_key"`
+	APIURL          string `json:"api_url"`
+	DefaultCity     string `json:"default_city"`
+	Units           string `json:"units"`
+	RefreshInterval string `json:"refresh_interval"`
+	Lang            string `json:"lang"`
+	Provider        string `json:"provider"`
+	WindUnit        string `json:"wind_unit"`
+	TimeFormat      string `json:"time_format"`
+}
+
+// fields returns the config fields by the environment variable that
+// overrides them
+func (c *Config) fields() map[string]*string {
+	return map[string]*string{
+		"API_KEY":          &c.APIKey,
+		"API_URL":          &c.APIURL,
+		"DEFAULT_CITY":     &c.DefaultCity,
+		"UNITS":            &c.Units,
+		"REFRESH_INTERVAL": &c.RefreshInterval,
+		"LANG":             &c.Lang,
+		"PROVIDER":         &c.Provider,
+		"WIND_UNIT":        &c.WindUnit,
+		"TIME_FORMAT":      &c.TimeFormat,
+	}
+}
+
+func (c Config) validate() error {
+	switch strings.ToLower(c.Provider) {
+	case "", PROVIDER_OPENWEATHERMAP:
+		if c.APIKey == "" {
+			return fmt.Errorf("api_key is required (or set API_KEY)")
+		}
+		if c.APIURL == "" {
+			return fmt.Errorf("api_url is required (or set API_URL)")
+		}
+	case PROVIDER_OPEN_METEO:
+	default:
+		return fmt.Errorf("provider: %q is not %s or %s", c.Provider, PROVIDER_OPENWEATHERMAP, PROVIDER_OPEN_METEO)
+	}
+	if c.Units != "" {
+		var unit Unit
+		if err := unit.UnmarshalText([]byte(c.Units)); err != nil {
+			return fmt.Errorf("units: %v, want metric or imperial", err)
+		}
+	}
+	switch strings.ToLower(c.WindUnit) {
+	case "", "kmh", "km/h", "ms", "m/s", "mph":
+	default:
+		return fmt.Errorf("wind_unit: %q is not kmh, ms or mph", c.WindUnit)
+	}
+	switch strings.ToLower(c.TimeFormat) {
+	case "", TIME_FORMAT_24H, TIME_FORMAT_12H:
+	default:
+		return fmt.Errorf("time_format: %q is not %s or %s", c.TimeFormat, TIME_FORMAT_24H, TIME_FORMAT_12H)
+	}
+	if c.RefreshInterval != "" {
+		if interval, err := time.ParseDuration(c.RefreshInterval); err != nil || interval <= 0 {
+			return fmt.Errorf("refresh_interval: %q is not a positive duration such as \"10m\"", c.RefreshInterval)
+		}
+	}
+	return nil
+}
+
+// Apply exports the merged values to the environment, where the rest of the
+// app reads its settings
+func (c *Config) Apply() {
+	for key, field := range c.fields() {
+		if *field != "" {
+			os.Setenv(key, *field)
+		}
+	}
+}
+
+// configPaths lists where config.json is looked for, in order
+func configPaths() []string {
+	paths := []string{CONFIG_FILE}
+	if dir, err := os.UserConfigDir(); err == nil {
+		paths = append(paths, filepath.Join(dir, CONFIG_DIR, CONFIG_FILE))
+	}
+	return paths
+}
+
+// loadConfig reads the first config.json found and overlays the environment
+// on it. With no file anywhere the error wraps os.ErrNotExist, so callers can
+// fall back to .env.
+func loadConfig() (Config, error) {
+	var config Config
+
+	for _, path := range configPaths() {
+		data, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return config, fmt.Errorf("failed to read %s: %v", path, err)
+		}
+
+		if err := json.Unmarshal(data, &config); err != nil {
+			return config, fmt.Errorf("failed to parse %s: %v", path, err)
+		}
+
+		for key, field := range config.fields() {
+			if value := os.Getenv(key); value != "" {
+				*field = value
+			}
+		}
+
+		if err := config.validate(); err != nil {
+			return config, fmt.Errorf("%s: %v", path, err)
+		}
+		return config, nil
+	}
+
+	return config, fmt.Errorf("no %s found: %w", CONFIG_FILE, os.ErrNotExist)
+}
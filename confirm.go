@@ -0,0 +1,61 @@
+package main
+
+import rl "github.com/gen2brain/raylib-go/raylib"
+
+// CONFIRM DIALOG GEOMETRY
+const (
+	CONFIRM_WIDTH  float32 = 380
+	CONFIRM_HEIGHT float32 = 140
+	CONFIRM_BUTTON float32 = 90
+)
+
+// CloseChoice is the answer to the save-before-exit prompt
+type CloseChoice int
+
+const (
+	CloseUndecided CloseChoice = iota
+	CloseSave
+	CloseDiscard
+	CloseCancel
+)
+
+// confirmBounds centers the dialog in the window
+func confirmBounds(screenWidth, screenHeight float32) rl.Rectangle {
+	return rl.NewRectangle((screenWidth-CONFIRM_WIDTH)/2, (screenHeight-CONFIRM_HEIGHT)/2, CONFIRM_WIDTH, CONFIRM_HEIGHT)
+}
+
+func confirmButtons(bounds rl.Rectangle) (yes, no rl.Rectangle) {
+	y := bounds.Y + bounds.Height - 50
+	yes = rl.NewRectangle(bounds.X+bounds.Width/2-CONFIRM_BUTTON-10, y, CONFIRM_BUTTON, 36)
+	no = rl.NewRectangle(bounds.X+bounds.Width/2+10, y, CONFIRM_BUTTON, 36)
+	return yes, no
+}
+
+// closeChoice reads this frame's answer: Y or the Yes button saves, N or the
+// No button quits without saving, and Escape goes back to the app
+func closeChoice(bounds rl.Rectangle) CloseChoice {
+	yes, no := confirmButtons(bounds)
+	switch {
+	case rl.IsKeyPressed(rl.KeyY) || buttonClicked(yes):
+		return CloseSave
+	case rl.IsKeyPressed(rl.KeyN) || buttonClicked(no):
+		return CloseDiscard
+	case rl.IsKeyPressed(rl.KeyEscape):
+		return CloseCancel
+	}
+	return CloseUndecided
+}
+
+// drawConfirm draws the save-before-exit prompt over a dimmed window
+func drawConfirm(font rl.Font, theme Theme, bounds rl.Rectangle) {
+	rl.DrawRectangle(0, 0, int32(rl.GetScreenWidth()), int32(rl.GetScreenHeight()), rl.Fade(theme.Background, 0.6))
+	rl.DrawRectangleRounded(bounds, 0.08, 8, theme.Box)
+	rl.DrawRectangleRoundedLinesEx(bounds, 0.08, 8, 2, theme.Warn)
+
+	rl.DrawTextEx(font, "Save before exit? Y/N", rl.NewVector2(bounds.X+20, bounds.Y+18), 24, 0, theme.Text)
+	rl.DrawTextEx(font, "Your last changes could not be saved", rl.NewVector2(bounds.X+20, bounds.Y+50), 16, 0, theme.Muted)
+
+	yes, no := confirmButtons(bounds)
+	drawButton(font, theme, yes, "Yes", true)
+	drawButton(font, theme, no, "No", true)
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// coordsPattern matches "lat,lon" input such as "51.5074,-0.1278"
+var coordsPattern = regexp.MustCompile(`^\s*([+-]?\d+(?:\.\d+)?)\s*,\s*([+-]?\d+(?:\.\d+)?)\s*$`)
+
+// isCoords reports whether input has the "lat,lon" form, valid or not
+func isCoords(input string) bool {
+	return coordsPattern.MatchString(input)
+}
+
+// parseCoords reads "lat,lon" input, rejecting values off the globe
+func parseCoords(input string) (float64, float64, error) {
+	match := coordsPattern.FindStringSubmatch(input)
+	if match == nil {
+		return 0, 0, fmt.Errorf("%q is not in lat,lon form", input)
+	}
+
+	lat, _ := strconv.ParseFloat(match[1], 64)
+	lon, _ := strconv.ParseFloat(match[2], 64)
+	if lat < -90 || lat > 90 {
+		return 0, 0, fmt.Errorf("latitude %v is out of range (-90 to 90)", lat)
+	}
+	if lon < -180 || lon > 180 {
+		return 0, 0, fmt.Errorf("longitude %v is out of range (-180 to 180)", lon)
+	}
+	return lat, lon, nil
+}
+
+// formatCoords labels a location that the API returned without a name
+func formatCoords(lat, lon float64) string {
+	return strconv.FormatFloat(lat, 'f', -1, 64) + "," + strconv.FormatFloat(lon, 'f', -1, 64)
+}
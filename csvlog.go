@@ -0,0 +1,134 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const CSV_LOG_FILE_DEFAULT = "weather_log.csv"
+
+var csvLogHeader = []string{"timestamp", "city", "temp", "feelslike", "humidity", "wind", "condition", "units"}
+
+// csvLog appends one row per successful fetch. Fetches finish on their own
+// goroutines, so writes are serialised by mu to keep rows whole.
+type csvLog struct {
+	mu   sync.Mutex
+	path string
+}
+
+// newCSVLog logs to path; an empty path gives nil, which logs nothing
+func newCSVLog(path string) *csvLog {
+	if path == "" {
+		return nil
+	}
+	return &csvLog{path: path}
+}
+
+// Append writes a row for weather, adding the header first if the file is new
+// or empty. Temperatures are in the data's unit, named in the units column,
+// and wind is as shown in the UI, km/h for metric and mph for imperial.
+func (l *csvLog) Append(weather WeatherData, now time.Time) error {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	file, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %v", l.path, err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %v", l.path, err)
+	}
+
+	wind := weather.WindSpeed
+	if weather.Unit != Fahrenheit {
+		wind = msToKmh(wind)
+	}
+
+	writer := csv.NewWriter(file)
+	if info.Size() == 0 {
+		writer.Write(csvLogHeader)
+	}
+	writer.Write([]string{
+		now.Format(time.RFC3339),
+		weather.Location,
+		strconv.Itoa(weather.Temperature),
+		strconv.Itoa(weather.FeelsLike),
+		strconv.Itoa(weather.Humidity),
+		strconv.FormatFloat(float64(wind), 'f', 1, 32),
+		weather.Condition,
+		weather.Unit.APIParam(),
+	})
+	writer.Flush()
+
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write %s: %v", l.path, err)
+	}
+	return nil
+}
+
+// Recent returns up to n of the latest temperatures logged for city, oldest
+// first, converted to unit. Rows written before the units column existed are
+// taken as metric. A missing file gives no temperatures and no error.
+func (l *csvLog) Recent(city string, n int, unit Unit) ([]int, error) {
+	if l == nil {
+		return nil, nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	file, err := os.Open(l.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %v", l.path, err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+
+	var temps []int
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %v", l.path, err)
+		}
+		if len(record) < 3 || !strings.EqualFold(record[1], city) {
+			continue
+		}
+
+		temp, err := strconv.Atoi(record[2])
+		if err != nil {
+			continue
+		}
+		rowUnit := Celsius
+		if len(record) > 7 {
+			rowUnit.UnmarshalText([]byte(record[7]))
+		}
+		temps = append(temps, unit.FromCelsius(rowUnit.ToCelsius(temp)))
+	}
+
+	if len(temps) > n {
+		temps = temps[len(temps)-n:]
+	}
+	return temps, nil
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+const (
+	MAX_RAW_RESPONSE   int     = 64 * 1024
+	DEBUG_FONT_SIZE    float32 = 14
+	DEBUG_LINE_HEIGHT  float32 = 16
+	DEBUG_WHEEL_LINES  float32 = 3
+	REDACTED_API_KEY   string  = "[REDACTED]"
+	TRUNCATED_RESPONSE string  = "\n... (truncated)"
+)
+
+// isDebugMode reports whether the DEBUG config flag is set
+func isDebugMode() bool {
+	enabled, _ := strconv.ParseBool(os.Getenv("DEBUG"))
+	return enabled
+}
+
+// redactAPIKey replaces every occurrence of the API key in text
+func redactAPIKey(text string) string {
+	if apiKey := os.Getenv("API_KEY"); apiKey != "" {
+		return strings.ReplaceAll(text, apiKey, REDACTED_API_KEY)
+	}
+	return text
+}
+
+// lastResponse keeps the most recent raw API body for the debug panel.
+// Fetches run on several goroutines, so it is guarded by a mutex.
+var lastResponse struct {
+	mu   sync.Mutex
+	body string
+}
+
+// recordRawResponse stores body pretty-printed, with the API key redacted and
+// the size capped at MAX_RAW_RESPONSE
+func recordRawResponse(body []byte) {
+	var pretty bytes.Buffer
+	if err := json.Indent(&pretty, body, "", "  "); err == nil {
+		body = pretty.Bytes()
+	}
+
+	text := redactAPIKey(string(body))
+	if len(text) > MAX_RAW_RESPONSE {
+		text = strings.ToValidUTF8(text[:MAX_RAW_RESPONSE], "") + TRUNCATED_RESPONSE
+	}
+
+	lastResponse.mu.Lock()
+	lastResponse.body = text
+	lastResponse.mu.Unlock()
+}
+
+func lastRawResponse() string {
+	lastResponse.mu.Lock()
+	defer lastResponse.mu.Unlock()
+	return lastResponse.body
+}
+
+// scrollDebugPanel applies the mouse wheel and Page Up/Down to scroll and
+// clamps it so the last line can reach the bottom of area but no further
+func scrollDebugPanel(scroll float32, area rl.Rectangle) float32 {
+	scroll -= rl.GetMouseWheelMove() * DEBUG_WHEEL_LINES * DEBUG_LINE_HEIGHT
+
+	page := area.Height - 2*DEBUG_LINE_HEIGHT
+	if rl.IsKeyPressed(rl.KeyPageDown) {
+		scroll += page
+	}
+	if rl.IsKeyPressed(rl.KeyPageUp) {
+		scroll -= page
+	}
+
+	lines := float32(strings.Count(lastRawResponse(), "\n") + 1)
+	maxScroll := max(0, lines*DEBUG_LINE_HEIGHT+20-area.Height)
+	return min(max(scroll, 0), maxScroll)
+}
+
+// drawDebugPanel draws the last raw response over area, scrolled down by
+// scroll pixels and clipped to the area
+func drawDebugPanel(font rl.Font, theme Theme, area rl.Rectangle, scroll float32) {
+	rl.DrawRectangleRec(area, rl.Fade(theme.Box, 0.97))
+	rl.DrawRectangleLinesEx(area, 2, theme.Text)
+
+	body := lastRawResponse()
+	if body == "" {
+		body = "No response yet"
+	}
+
+	rl.BeginScissorMode(int32(area.X), int32(area.Y), int32(area.Width), int32(area.Height))
+	for i, line := range strings.Split(body, "\n") {
+		y := area.Y + 10 + float32(i)*DEBUG_LINE_HEIGHT - scroll
+		if y+DEBUG_LINE_HEIGHT < area.Y {
+			continue
+		}
+		if y > area.Y+area.Height {
+			break
+		}
+		rl.DrawTextEx(font, line, rl.NewVector2(area.X+10, y), DEBUG_FONT_SIZE, 0, theme.Text)
+	}
+	rl.EndScissorMode()
+}
@@ -0,0 +1,9 @@
+package main
+
+import "encoding/json"
+
+// marshalWeather is the JSON form of the weather shared by every export path.
+// The units travel inside WeatherData, so readers can tell °C from °F.
+func marshalWeather(weather WeatherData) ([]byte, error) {
+	return json.MarshalIndent(weather, "", "  ")
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// FAVORITES_MAX MATCHES THE 1-9 QUICK-SWITCH KEYS. A LABEL IS CAPPED SO IT
+// FITS THE FAVORITES COLUMN WHILE IT IS EDITED.
+const (
+	FAVORITES_MAX            = 9
+	FAVORITES_ROW_HEIGHT     = 16
+	FAVORITE_LABEL_MAX_CHARS = 16
+)
+
+// addFavorite appends city unless it is already listed or the list is full.
+// The bool reports whether the list changed.
+func addFavorite(favorites []string, city string) ([]string, bool) {
+	if city == "" || len(favorites) >= FAVORITES_MAX {
+		return favorites, false
+	}
+	for _, favorite := range favorites {
+		if normalizeCity(favorite) == normalizeCity(city) {
+			return favorites, false
+		}
+	}
+	return append(favorites, city), true
+}
+
+// favoriteLabel is the user's label for city, or city itself without one.
+// Labels are keyed by the normalized city name.
+func favoriteLabel(labels map[string]string, city string) string {
+	if label := labels[normalizeCity(city)]; label != "" {
+		return label
+	}
+	return city
+}
+
+// setFavoriteLabel stores label for city, or removes it when label is blank
+func setFavoriteLabel(labels map[string]string, city, label string) map[string]string {
+	label = strings.TrimSpace(label)
+	if label == "" {
+		delete(labels, normalizeCity(city))
+		return labels
+	}
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	labels[normalizeCity(city)] = label
+	return labels
+}
+
+// withLabel swaps the location for the user's label. It is for drawing
+// only; fetches keep using the real name.
+func withLabel(weather WeatherData, labels map[string]string) WeatherData {
+	weather.Location = favoriteLabel(labels, weather.Location)
+	return weather
+}
+
+// favoriteKey returns the favorite index for a pressed 1-9 key, or -1
+func favoriteKey(favorites []string) int {
+	for i := range favorites {
+		if rl.IsKeyPressed(rl.KeyOne + int32(i)) {
+			return i
+		}
+	}
+	return -1
+}
+
+func favoriteRow(area rl.Rectangle, index int) rl.Rectangle {
+	return rl.NewRectangle(area.X, area.Y+float32(index*FAVORITES_ROW_HEIGHT), area.Width, FAVORITES_ROW_HEIGHT)
+}
+
+// clickedFavorite returns the index of the favorite clicked this frame, or -1
+func clickedFavorite(favorites []string, area rl.Rectangle) int {
+	for i := range favorites {
+		if buttonClicked(favoriteRow(area, i)) {
+			return i
+		}
+	}
+	return -1
+}
+
+// rightClickedFavorite returns the index of the favorite right-clicked this
+// frame, or -1
+func rightClickedFavorite(favorites []string, area rl.Rectangle) int {
+	if !rl.IsMouseButtonPressed(rl.MouseRightButton) {
+		return -1
+	}
+	for i := range favorites {
+		if rl.CheckCollisionPointRec(rl.GetMousePosition(), favoriteRow(area, i)) {
+			return i
+		}
+	}
+	return -1
+}
+
+// drawFavorites lists favorites by label with their hotkey, highlighting the
+// hovered row. The row at editing, if any, is drawn as a field holding edit.
+func drawFavorites(font rl.Font, theme Theme, favorites []string, labels map[string]string, area rl.Rectangle, editing int, edit *inputBuffer) {
+	mouse := rl.GetMousePosition()
+
+	for i, favorite := range favorites {
+		row := favoriteRow(area, i)
+
+		if i == editing {
+			rl.DrawRectangleRec(row, theme.InputBox)
+			rl.DrawRectangleLinesEx(row, 1, theme.Accent)
+
+			text := edit.String()
+			rl.DrawTextEx(font, text, rl.NewVector2(row.X+2, row.Y+1), 14, 0, theme.Text)
+			caretX := row.X + 2 + rl.MeasureTextEx(font, string([]rune(text)[:edit.Cursor()]), 14, 0).X
+			rl.DrawRectangleRec(rl.NewRectangle(caretX, row.Y+2, 1, row.Height-4), theme.Text)
+			continue
+		}
+
+		color := theme.Muted
+		if rl.CheckCollisionPointRec(mouse, row) {
+			color = theme.Accent
+		}
+
+		label := truncateText(font, fmt.Sprintf("%d %s", i+1, favoriteLabel(labels, favorite)), 14, row.Width)
+		rl.DrawTextEx(font, label, rl.NewVector2(row.X, row.Y), 14, 0, color)
+	}
+}
@@ -0,0 +1,29 @@
+package main
+
+import rl "github.com/gen2brain/raylib-go/raylib"
+
+// FEELS_LIKE_DELTA_DEFAULT is how many degrees Celsius feels-like may differ
+// from the temperature before the panel flags it
+const FEELS_LIKE_DELTA_DEFAULT = 3
+
+// feelsLikeDelta is the flagging threshold, read from FEELS_LIKE_DELTA in init
+var feelsLikeDelta = FEELS_LIKE_DELTA_DEFAULT
+
+// feelsLikeDiverges reports whether wind chill or heat index moves feels-like
+// more than feelsLikeDelta from the temperature. Both are compared in
+// Celsius, so the threshold means the same in either unit.
+func feelsLikeDiverges(weather WeatherData) bool {
+	delta := weather.Unit.ToCelsius(weather.Temperature) - weather.Unit.ToCelsius(weather.FeelsLike)
+	return max(delta, -delta) > feelsLikeDelta
+}
+
+// drawWarningMark draws a warning triangle with a "!" in cutout color, with
+// its top left at origin
+func drawWarningMark(origin rl.Vector2, size float32, color, cutout rl.Color) {
+	x, y := origin.X, origin.Y
+	rl.DrawTriangle(rl.NewVector2(x+size/2, y), rl.NewVector2(x, y+size), rl.NewVector2(x+size, y+size), color)
+
+	bar := size / 8
+	rl.DrawRectangleRec(rl.NewRectangle(x+size/2-bar/2, y+size*0.35, bar, size*0.35), cutout)
+	rl.DrawRectangleRec(rl.NewRectangle(x+size/2-bar/2, y+size*0.78, bar, bar), cutout)
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"log/slog"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// fontRanges are the codepoint ranges baked into the font atlas: printable
+// ASCII, Latin-1 and Latin Extended-A, plus the widget's storm symbol. That
+// covers the degree sign and most accented city names, e.g. Zürich or São
+// Paulo. Anything else draws as "?".
+var fontRanges = [][2]rune{
+	{0x20, 0x7E},
+	{0xA0, 0x17F},
+	{STORM_SYMBOL, STORM_SYMBOL},
+}
+
+// inFontRanges reports whether r is one of the codepoints loaded from the font
+func inFontRanges(r rune) bool {
+	for _, span := range fontRanges {
+		if r >= span[0] && r <= span[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// fontCodepoints expands fontRanges for LoadFontEx
+func fontCodepoints() []rune {
+	var codepoints []rune
+	for _, span := range fontRanges {
+		for r := span[0]; r <= span[1]; r++ {
+			codepoints = append(codepoints, r)
+		}
+	}
+	return codepoints
+}
+
+// requiredGlyphs are the symbols temperatures and humidity are drawn with.
+// Both are inside fontRanges, so a missing one means the font file itself has
+// no glyph for it.
+var requiredGlyphs = []rune{'°', '%'}
+
+// DEGREE_FALLBACK stands in for the degree sign when the font has no glyph
+// for it, so "12°C" draws as "12 C" rather than with a missing-glyph box
+const DEGREE_FALLBACK = " "
+
+// degreeSign is what temperatures are drawn with. loadFont swaps in
+// DEGREE_FALLBACK if the loaded font cannot draw the real sign.
+var degreeSign = "°"
+
+// fontHasGlyph reports whether font has its own glyph for r. raylib answers a
+// lookup for a missing codepoint with the "?" glyph, so the glyph's value
+// tells the two apart.
+func fontHasGlyph(font rl.Font, r rune) bool {
+	return rl.GetGlyphInfo(font, r).Value == r
+}
+
+// checkGlyphs logs every required symbol hasGlyph says the loaded font
+// cannot draw and returns the degree sign to use
+func checkGlyphs(hasGlyph func(r rune) bool) string {
+	for _, r := range requiredGlyphs {
+		if !hasGlyph(r) {
+			slog.Warn("Font has no glyph for a required symbol", "symbol", string(r))
+		}
+	}
+	if !hasGlyph('°') {
+		return DEGREE_FALLBACK
+	}
+	return "°"
+}
+
+// loadFont loads the UI font, falling back to raylib's default font if the
+// file is missing or unreadable. A failed load returns a zero Font, which
+// DrawTextEx silently swaps for the default font while MeasureTextEx measures
+// as zero width, so the caret would sit at the start of the text. Returning
+// the default font itself keeps drawing and measuring on the same instance.
+func loadFont(path string, size int32) rl.Font {
+	loaded := rl.LoadFontEx(path, size, fontCodepoints())
+	valid := rl.IsFontValid(loaded)
+	if valid {
+		rl.SetTextureFilter(loaded.Texture, rl.FilterBilinear)
+	} else {
+		slog.Warn("Failed to load font, using the default font", "path", path)
+	}
+
+	font := chooseFont(loaded, valid, rl.GetFontDefault)
+	degreeSign = checkGlyphs(func(r rune) bool { return fontHasGlyph(font, r) })
+	return font
+}
+
+// chooseFont picks the single instance every draw and measure call uses: the
+// loaded font, or the one fallback returns if loading failed. fallback is
+// only called when it is needed.
+func chooseFont(loaded rl.Font, valid bool, fallback func() rl.Font) rl.Font {
+	if valid {
+		return loaded
+	}
+	return fallback()
+}
+
+// truncateText shortens text with a trailing "..." until it fits maxWidth
+func truncateText(font rl.Font, text string, size float32, maxWidth float32) string {
+	if rl.MeasureTextEx(font, text, size, 0).X <= maxWidth {
+		return text
+	}
+
+	runes := []rune(text)
+	for len(runes) > 0 {
+		runes = runes[:len(runes)-1]
+		candidate := string(runes) + "..."
+		if rl.MeasureTextEx(font, candidate, size, 0).X <= maxWidth {
+			return candidate
+		}
+	}
+	return ""
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+func TestChooseFont(t *testing.T) {
+	loaded := rl.Font{BaseSize: 48, Texture: rl.Texture2D{ID: 7}}
+	fallback := rl.Font{BaseSize: 10, Texture: rl.Texture2D{ID: 1}}
+
+	calls := 0
+	defaultFont := func() rl.Font {
+		calls++
+		return fallback
+	}
+
+	if font := chooseFont(loaded, true, defaultFont); font != loaded {
+		t.Errorf("valid load: got %+v, want the loaded font", font)
+	}
+	if calls != 0 {
+		t.Errorf("valid load asked for the default font %d times", calls)
+	}
+
+	// A FAILED LOAD MUST HAND BACK THE FALLBACK ITSELF, NOT THE ZERO FONT
+	// DrawTextEx WOULD SILENTLY SWAP WHILE MeasureTextEx MEASURES IT AS EMPTY
+	failed := rl.Font{}
+	if font := chooseFont(failed, false, defaultFont); font != fallback {
+		t.Errorf("failed load: got %+v, want the fallback font", font)
+	}
+	if calls != 1 {
+		t.Errorf("failed load asked for the default font %d times, want 1", calls)
+	}
+}
+
+func TestRequiredGlyphsLoaded(t *testing.T) {
+	codepoints := make(map[rune]bool)
+	for _, r := range fontCodepoints() {
+		codepoints[r] = true
+	}
+	for _, r := range requiredGlyphs {
+		if !inFontRanges(r) || !codepoints[r] {
+			t.Errorf("%q (%U) is not in fontRanges, so it would draw as ?", r, r)
+		}
+	}
+}
+
+func TestCheckGlyphs(t *testing.T) {
+	tests := []struct {
+		name    string
+		missing rune
+		want    string
+	}{
+		{"full font", 0, "°"},
+		{"no degree sign", '°', DEGREE_FALLBACK},
+		{"no percent sign", '%', "°"},
+	}
+	for _, test := range tests {
+		got := checkGlyphs(func(r rune) bool { return r != test.missing })
+		if got != test.want {
+			t.Errorf("%s: degree sign = %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestDegreeFallbackReachesTemperatures(t *testing.T) {
+	saved := degreeSign
+	t.Cleanup(func() { degreeSign = saved })
+
+	degreeSign = checkGlyphs(func(r rune) bool { return r != '°' })
+	if got := formatTemperature(12, Celsius); got != "12 C" {
+		t.Errorf("formatTemperature without a degree glyph = %q, want %q", got, "12 C")
+	}
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// FORECAST ENTRIES ARE 3 HOURS APART, SO EVERY 8TH ONE IS A NEW DAY
+const (
+	FORECAST_STEP int = 8
+	FORECAST_DAYS int = 5
+)
+
+type ForecastEntry struct {
+	Time        time.Time
+	Temperature int
+	Condition   string
+	Unit        Unit
+}
+
+// forecastResult is a forecast fetched for the forecast view, tagged with
+// the city and unit it was asked for
+type forecastResult struct {
+	city     string
+	unit     Unit
+	forecast []ForecastEntry
+	err      error
+}
+
+type openWeatherForecast struct {
+	List []struct {
+		Dt   int64 `json:"dt"`
+		Main struct {
+			Temp float64 `json:"temp"`
+		} `json:"main"`
+		Weather []struct {
+			Main string `json:"main"`
+		} `json:"weather"`
+	} `json:"list"`
+}
+
+// forecastURL is ForecastURL if set, otherwise the forecast endpoint next to
+// BaseURL, keeping BaseURL's query
+func (c *WeatherClient) forecastURL() string {
+	if c.ForecastURL != "" {
+		return c.ForecastURL
+	}
+
+	apiURL, err := url.Parse(c.BaseURL)
+	if err != nil {
+		return ""
+	}
+	apiURL.Path = strings.TrimSuffix(apiURL.Path, "/weather") + "/forecast"
+	return apiURL.String()
+}
+
+// Forecast fetches one reading a day for the next FORECAST_DAYS days
+func (c *WeatherClient) Forecast(ctx context.Context, cityName string) ([]ForecastEntry, error) {
+	if strings.TrimSpace(cityName) == "" {
+		return nil, ErrEmptyQuery
+	}
+	requestURL, err := withQuery(c.forecastURL(), c.query(cityName))
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := retryGet(ctx, c.httpClient(), requestURL)
+	if err != nil {
+		return nil, requestError("fetch forecast", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, requestError("read response", err)
+	}
+	recordRawResponse(body)
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return nil, rateLimitError(resp, time.Now())
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API error (%d): %s", resp.StatusCode, string(body))
+	}
+
+	var apiResp openWeatherForecast
+	if err := decodeResponse(body, &apiResp); err != nil {
+		return nil, err
+	}
+
+	var forecast []ForecastEntry
+	for i := 0; i < len(apiResp.List) && len(forecast) < FORECAST_DAYS; i += FORECAST_STEP {
+		item := apiResp.List[i]
+		entry := ForecastEntry{
+			Time:        time.Unix(item.Dt, 0),
+			Temperature: int(item.Main.Temp),
+			Unit:        c.Units,
+		}
+		if len(item.Weather) > 0 {
+			entry.Condition = item.Weather[0].Main
+		}
+		forecast = append(forecast, entry)
+	}
+
+	return forecast, nil
+}
+
+// drawViewTabs marks which of the current and forecast panels is shown
+func drawViewTabs(font rl.Font, theme Theme, origin rl.Vector2, showForecast bool) {
+	currentColor, forecastColor := theme.Accent, theme.Muted
+	if showForecast {
+		currentColor, forecastColor = theme.Muted, theme.Accent
+	}
+
+	rl.DrawTextEx(font, "Current", origin, 16, 0, currentColor)
+	rl.DrawTextEx(font, "Forecast", rl.NewVector2(origin.X+80, origin.Y), 16, 0, forecastColor)
+	rl.DrawTextEx(font, "(TAB)", rl.NewVector2(origin.X+170, origin.Y), 16, 0, theme.InputBox)
+}
+
+// drawForecast draws one column per day inside the weather panel
+func drawForecast(font rl.Font, theme Theme, forecast []ForecastEntry, panel rl.Rectangle) {
+	rl.DrawRectangleRec(panel, theme.Box)
+	rl.DrawRectangleLinesEx(panel, 2, theme.Text)
+
+	if len(forecast) == 0 {
+		rl.DrawTextEx(font, "Loading forecast...", rl.NewVector2(panel.X+20, panel.Y+20), 20, 0, theme.Text)
+		return
+	}
+
+	columnWidth := panel.Width / float32(FORECAST_DAYS)
+	for i, entry := range forecast {
+		x := panel.X + 20 + float32(i)*columnWidth
+
+		rl.DrawTextEx(font, entry.Time.Format("Mon"), rl.NewVector2(x, panel.Y+20), 20, 0, theme.Accent)
+		rl.DrawTextEx(font, formatTemperature(entry.Temperature, entry.Unit), rl.NewVector2(x, panel.Y+60), 32, 0, theme.Strong)
+		rl.DrawTextEx(font, conditionLabel(entry.Condition), rl.NewVector2(x, panel.Y+110), 18, 0, theme.Text)
+	}
+}
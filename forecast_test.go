@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// forecastList builds a /forecast body with count 3-hourly entries, the
+// temperature of each being its index
+func forecastList(count int) string {
+	entries := make([]string, count)
+	for i := range entries {
+		entries[i] = fmt.Sprintf(`{"dt": %d, "main": {"temp": %d.7}, "weather": [{"main": "Rain"}]}`, 1700000000+i*3*3600, i)
+	}
+	return `{"list": [` + strings.Join(entries, ",") + `]}`
+}
+
+func TestWeatherClientForecast(t *testing.T) {
+	// THE ENVIRONMENT MUST NOT LEAK INTO A CLIENT BUILT BY HAND
+	t.Setenv("API_KEY", "env-key")
+	t.Setenv("API_URL", "http://127.0.0.1:1/data/2.5/weather")
+	t.Setenv("FORECAST_URL", "http://127.0.0.1:1/data/2.5/forecast")
+
+	var got url.Values
+	mux := http.NewServeMux()
+	mux.HandleFunc("/data/2.5/forecast", func(w http.ResponseWriter, r *http.Request) {
+		got = r.URL.Query()
+		w.Write([]byte(forecastList(40)))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	client := &WeatherClient{
+		APIKey:     "test-key",
+		BaseURL:    server.URL + "/data/2.5/weather",
+		HTTPClient: server.Client(),
+		Units:      Fahrenheit,
+		Lang:       "de",
+	}
+	forecast, err := client.Forecast(context.Background(), " Köln ")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{"appid": "test-key", "units": "imperial", "lang": "de", "q": "Köln"}
+	for key, value := range want {
+		if got.Get(key) != value {
+			t.Errorf("%s = %q, want %q", key, got.Get(key), value)
+		}
+	}
+
+	if len(forecast) != FORECAST_DAYS {
+		t.Fatalf("got %d entries, want %d", len(forecast), FORECAST_DAYS)
+	}
+	for i, entry := range forecast {
+		if entry.Temperature != i*FORECAST_STEP || entry.Condition != "Rain" || entry.Unit != Fahrenheit {
+			t.Errorf("entry %d = %+v, want %d° Rain in %v", i, entry, i*FORECAST_STEP, Fahrenheit)
+		}
+	}
+}
+
+func TestWeatherClientForecastURL(t *testing.T) {
+	tests := []struct {
+		client WeatherClient
+		want   string
+	}{
+		{WeatherClient{BaseURL: "https://api.example.com/data/2.5/weather"}, "https://api.example.com/data/2.5/forecast"},
+		{WeatherClient{BaseURL: "https://api.example.com/data/2.5/weather?mode=json"}, "https://api.example.com/data/2.5/forecast?mode=json"},
+		{WeatherClient{BaseURL: "https://api.example.com/data/2.5/weather", ForecastURL: "https://other.example.com/f"}, "https://other.example.com/f"},
+	}
+	for _, test := range tests {
+		if got := test.client.forecastURL(); got != test.want {
+			t.Errorf("forecastURL() for %+v = %q, want %q", test.client, got, test.want)
+		}
+	}
+}
+
+func TestWeatherClientForecastShortList(t *testing.T) {
+	server := serveBody(t, http.StatusOK, forecastList(10))
+
+	forecast, err := testClient(server).Forecast(context.Background(), "Oslo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(forecast) != 2 {
+		t.Errorf("got %d entries from 10 readings, want 2", len(forecast))
+	}
+}
+
+func TestWeatherClientForecastErrors(t *testing.T) {
+	if _, err := (&WeatherClient{}).Forecast(context.Background(), "  "); err != ErrEmptyQuery {
+		t.Errorf("blank city: err = %v, want ErrEmptyQuery", err)
+	}
+
+	server := serveBody(t, http.StatusUnauthorized, `{"cod": 401}`)
+	if _, err := testClient(server).Forecast(context.Background(), "Oslo"); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("401: err = %v, want an API error", err)
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"time"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// FRAME RATES FOR THE RENDER LOOP. AFTER IDLE_AFTER WITHOUT INPUT OR
+// ANIMATION IT DROPS TO FPS_IDLE, AND ANY ACTIVITY BRINGS BACK FPS_ACTIVE.
+const (
+	FPS_ACTIVE int32 = 60
+	FPS_IDLE   int32 = 15
+	IDLE_AFTER       = 2 * time.Second
+)
+
+// frameRate lowers the target FPS while the app sits idle
+type frameRate struct {
+	lastActivity time.Time
+	target       int32
+}
+
+func newFrameRate(now time.Time) *frameRate {
+	rl.SetTargetFPS(FPS_ACTIVE)
+	return &frameRate{lastActivity: now, target: FPS_ACTIVE}
+}
+
+// userInput reports whether the mouse or keyboard did anything this frame.
+// Nothing else reads the key queue, so draining it here is safe.
+func userInput() bool {
+	delta := rl.GetMouseDelta()
+	if delta.X != 0 || delta.Y != 0 || rl.GetMouseWheelMove() != 0 || rl.IsWindowResized() {
+		return true
+	}
+	for _, button := range []rl.MouseButton{rl.MouseLeftButton, rl.MouseRightButton, rl.MouseMiddleButton} {
+		if rl.IsMouseButtonDown(button) {
+			return true
+		}
+	}
+	return rl.GetKeyPressed() != 0
+}
+
+// Update picks the target FPS for the coming frames. busy is true while a
+// fetch or animation runs, which holds the high rate like input does.
+func (f *frameRate) Update(now time.Time, busy bool) {
+	if busy || userInput() {
+		f.lastActivity = now
+	}
+
+	target := FPS_ACTIVE
+	if now.Sub(f.lastActivity) >= IDLE_AFTER {
+		target = FPS_IDLE
+	}
+	if target != f.target {
+		rl.SetTargetFPS(target)
+		f.target = target
+	}
+}
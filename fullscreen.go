@@ -0,0 +1,25 @@
+package main
+
+import rl "github.com/gen2brain/raylib-go/raylib"
+
+// fullscreenToggle switches between the window and borderless fullscreen on
+// the current monitor. ToggleFullscreen keeps the GL context, so the font
+// atlas and icon textures stay valid and nothing is reloaded. The window
+// size from before going fullscreen is restored on the way back.
+type fullscreenToggle struct {
+	windowedWidth  int
+	windowedHeight int
+}
+
+func (f *fullscreenToggle) Toggle() {
+	if rl.IsWindowFullscreen() {
+		rl.ToggleFullscreen()
+		rl.SetWindowSize(f.windowedWidth, f.windowedHeight)
+		return
+	}
+
+	f.windowedWidth, f.windowedHeight = rl.GetScreenWidth(), rl.GetScreenHeight()
+	monitor := rl.GetCurrentMonitor()
+	rl.SetWindowSize(rl.GetMonitorWidth(monitor), rl.GetMonitorHeight(monitor))
+	rl.ToggleFullscreen()
+}
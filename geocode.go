@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+const (
+	GEOCODE_LIMIT     int = 5
+	GEOCODE_CACHE_TTL     = 24 * time.Hour
+)
+
+type GeoLocation struct {
+	Name    string  `json:"name"`
+	State   string  `json:"state"`
+	Country string  `json:"country"`
+	Lat     float64 `json:"lat"`
+	Lon     float64 `json:"lon"`
+}
+
+// geocodeURL is GeoURL if set, otherwise the geocoding endpoint on BaseURL's host
+func (c *WeatherClient) geocodeURL() string {
+	if c.GeoURL != "" {
+		return c.GeoURL
+	}
+
+	apiURL, err := url.Parse(c.BaseURL)
+	if err != nil {
+		return ""
+	}
+	return apiURL.Scheme + "://" + apiURL.Host + "/geo/1.0/direct"
+}
+
+// Label formats the location as "City, Country", with the state in between
+// when the API gives one
+func (l GeoLocation) Label() string {
+	if l.State != "" {
+		return fmt.Sprintf("%s, %s, %s", l.Name, l.State, l.Country)
+	}
+	return fmt.Sprintf("%s, %s", l.Name, l.Country)
+}
+
+// Query is the q= value that fetches weather for this exact place
+func (l GeoLocation) Query() string {
+	return l.Name + "," + l.Country
+}
+
+// Geocode lists up to GEOCODE_LIMIT places matching query
+func (c *WeatherClient) Geocode(ctx context.Context, query string) ([]GeoLocation, error) {
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("limit", fmt.Sprint(GEOCODE_LIMIT))
+	params.Set("appid", c.APIKey)
+
+	requestURL, err := withQuery(c.geocodeURL(), params)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := retryGet(ctx, c.httpClient(), requestURL)
+	if err != nil {
+		return nil, requestError("geocode", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API error (%d): %s", resp.StatusCode, string(body))
+	}
+
+	var locations []GeoLocation
+	if err := decodeResponse(body, &locations); err != nil {
+		return nil, err
+	}
+
+	return locations, nil
+}
+
+type geocodeEntry struct {
+	locations []GeoLocation
+	expiresAt time.Time
+}
+
+// geocodeCache remembers geocoding results by normalized query. It is kept
+// apart from the weather cache because places change far less often than
+// weather, and it is shared by goroutines, so every access takes the lock.
+type geocodeCache struct {
+	mu      sync.Mutex
+	entries map[string]geocodeEntry
+	lookup  func(ctx context.Context, query string) ([]GeoLocation, error)
+}
+
+// newGeocodeCache caches lookup, usually a Provider's Geocode
+func newGeocodeCache(lookup func(ctx context.Context, query string) ([]GeoLocation, error)) *geocodeCache {
+	return &geocodeCache{entries: make(map[string]geocodeEntry), lookup: lookup}
+}
+
+// Lookup returns cached locations for query, calling the API on a miss or expiry
+func (c *geocodeCache) Lookup(ctx context.Context, query string) ([]GeoLocation, error) {
+	key := normalizeCity(query)
+
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.locations, nil
+	}
+
+	locations, err := c.lookup(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.entries[key] = geocodeEntry{locations: locations, expiresAt: time.Now().Add(GEOCODE_CACHE_TTL)}
+	c.mu.Unlock()
+
+	return locations, nil
+}
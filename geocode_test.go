@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestGeocodeCacheConcurrent(t *testing.T) {
+	var calls atomic.Int32
+	cache := newGeocodeCache(func(ctx context.Context, query string) ([]GeoLocation, error) {
+		calls.Add(1)
+		return []GeoLocation{{Name: normalizeCity(query)}}, nil
+	})
+
+	queries := []string{"Paris", " paris ", "Lyon", "LYON", "Nice"}
+
+	var wg sync.WaitGroup
+	for worker := range 16 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 100 {
+				query := queries[(worker+i)%len(queries)]
+				locations, err := cache.Lookup(context.Background(), query)
+				if err != nil || len(locations) != 1 || locations[0].Name != normalizeCity(query) {
+					t.Errorf("Lookup(%q) = %v, %v", query, locations, err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	// EVERY KEY IS WARM NOW, SO NOTHING MORE REACHES THE LOOKUP
+	before := calls.Load()
+	for _, query := range queries {
+		cache.Lookup(context.Background(), query)
+	}
+	if calls.Load() != before {
+		t.Errorf("warm lookups called the API %d more times", calls.Load()-before)
+	}
+}
+
+func TestGeocodeCacheSkipsErrors(t *testing.T) {
+	var calls atomic.Int32
+	cache := newGeocodeCache(func(ctx context.Context, query string) ([]GeoLocation, error) {
+		calls.Add(1)
+		return nil, fmt.Errorf("offline")
+	})
+
+	for range 2 {
+		if _, err := cache.Lookup(context.Background(), "Paris"); err == nil {
+			t.Fatal("Lookup succeeded, want the lookup error")
+		}
+	}
+	if calls.Load() != 2 {
+		t.Errorf("lookup called %d times, want 2: errors must not be cached", calls.Load())
+	}
+}
+
+func TestWeatherClientGeocode(t *testing.T) {
+	// THE ENVIRONMENT MUST NOT LEAK INTO A CLIENT BUILT BY HAND
+	t.Setenv("API_KEY", "env-key")
+	t.Setenv("GEO_URL", "http://127.0.0.1:1/geo/1.0/direct")
+
+	var got url.Values
+	mux := http.NewServeMux()
+	mux.HandleFunc("/geo/1.0/direct", func(w http.ResponseWriter, r *http.Request) {
+		got = r.URL.Query()
+		w.Write([]byte(`[
+			{"name": "Springfield", "state": "Illinois", "country": "US", "lat": 39.8, "lon": -89.64},
+			{"name": "Springfield", "country": "AU", "lat": -33.7, "lon": 150.6}
+		]`))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	client := &WeatherClient{APIKey: "test-key", BaseURL: server.URL + "/data/2.5/weather", HTTPClient: server.Client()}
+	locations, err := client.Geocode(context.Background(), "Springfield")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Get("appid") != "test-key" || got.Get("q") != "Springfield" || got.Get("limit") != fmt.Sprint(GEOCODE_LIMIT) {
+		t.Errorf("query = %v, want the client's key, the query and GEOCODE_LIMIT", got)
+	}
+	want := []string{"Springfield, Illinois, US", "Springfield, AU"}
+	if len(locations) != len(want) {
+		t.Fatalf("got %d locations, want %d", len(locations), len(want))
+	}
+	for i, label := range want {
+		if locations[i].Label() != label {
+			t.Errorf("location %d = %q, want %q", i, locations[i].Label(), label)
+		}
+	}
+}
+
+func TestWeatherClientGeocodeURL(t *testing.T) {
+	tests := []struct {
+		client WeatherClient
+		want   string
+	}{
+		{WeatherClient{BaseURL: "https://api.example.com/data/2.5/weather"}, "https://api.example.com/geo/1.0/direct"},
+		{WeatherClient{BaseURL: "http://localhost:8080/weather?mode=json"}, "http://localhost:8080/geo/1.0/direct"},
+		{WeatherClient{BaseURL: "https://api.example.com/data/2.5/weather", GeoURL: "https://geo.example.com/direct"}, "https://geo.example.com/direct"},
+	}
+	for _, test := range tests {
+		if got := test.client.geocodeURL(); got != test.want {
+			t.Errorf("geocodeURL() for %+v = %q, want %q", test.client, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+// HISTORY_MAX caps how many past searches are kept
+const HISTORY_MAX = 50
+
+// searchHistory recalls past searches shell style. Up walks back from the
+// newest entry; walking down past the newest restores what was being typed.
+type searchHistory struct {
+	entries []string
+	// position while recalling; len(entries) means not recalling
+	index int
+	draft string
+}
+
+func newSearchHistory(entries []string) *searchHistory {
+	return &searchHistory{entries: entries, index: len(entries)}
+}
+
+// Add records query, skipping a repeat of the newest entry, and ends any recall
+func (h *searchHistory) Add(query string) {
+	if query != "" && (len(h.entries) == 0 || h.entries[len(h.entries)-1] != query) {
+		h.entries = append(h.entries, query)
+		if len(h.entries) > HISTORY_MAX {
+			h.entries = h.entries[len(h.entries)-HISTORY_MAX:]
+		}
+	}
+	h.index = len(h.entries)
+}
+
+// Older returns the entry before the current one. current is kept as the
+// draft when recall starts, so Newer can bring it back.
+func (h *searchHistory) Older(current string) (string, bool) {
+	if h.index == 0 {
+		return "", false
+	}
+	if h.index == len(h.entries) {
+		h.draft = current
+	}
+	h.index--
+	return h.entries[h.index], true
+}
+
+// Newer returns the entry after the current one, or the draft past the end
+func (h *searchHistory) Newer() (string, bool) {
+	if h.index >= len(h.entries) {
+		return "", false
+	}
+	h.index++
+	if h.index == len(h.entries) {
+		return h.draft, true
+	}
+	return h.entries[h.index], true
+}
+
+// Entries returns the history oldest first, for saving
+func (h *searchHistory) Entries() []string {
+	return append([]string(nil), h.entries...)
+}
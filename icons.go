@@ -0,0 +1,70 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// ICON_SIZE is the drawn size in pixels, whatever the PNG's own size
+const (
+	ICON_DIR  string  = "resource/icons"
+	ICON_SIZE float32 = 48
+)
+
+// conditionIcons names the PNG in ICON_DIR drawn for each condition
+var conditionIcons = map[string]string{
+	"Clear":        "sun.png",
+	"Clouds":       "cloud.png",
+	"Drizzle":      "rain.png",
+	"Rain":         "rain.png",
+	"Snow":         "snow.png",
+	"Thunderstorm": "storm.png",
+}
+
+// iconSet holds the loaded textures by file name
+type iconSet map[string]rl.Texture2D
+
+// loadIcons loads every icon found in dir. Missing or unreadable files are
+// skipped, and those conditions are drawn as text instead.
+func loadIcons(dir string) iconSet {
+	icons := make(iconSet)
+	for _, file := range conditionIcons {
+		if _, ok := icons[file]; ok {
+			continue
+		}
+
+		path := filepath.Join(dir, file)
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+
+		texture := rl.LoadTexture(path)
+		if rl.IsTextureValid(texture) {
+			rl.SetTextureFilter(texture, rl.FilterBilinear)
+			icons[file] = texture
+		}
+	}
+	return icons
+}
+
+// Unload frees every texture; call it before the window closes
+func (icons iconSet) Unload() {
+	for _, texture := range icons {
+		rl.UnloadTexture(texture)
+	}
+}
+
+// Icon returns the texture for condition, if one was loaded
+func (icons iconSet) Icon(condition string) (rl.Texture2D, bool) {
+	texture, ok := icons[conditionIcons[condition]]
+	return texture, ok
+}
+
+// drawIcon draws texture scaled to ICON_SIZE with its top left at position
+func drawIcon(texture rl.Texture2D, position rl.Vector2) {
+	source := rl.NewRectangle(0, 0, float32(texture.Width), float32(texture.Height))
+	dest := rl.NewRectangle(position.X, position.Y, ICON_SIZE, ICON_SIZE)
+	rl.DrawTexturePro(texture, source, dest, rl.NewVector2(0, 0), 0, rl.White)
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestConditionIconsShipped checks that every icon conditionIcons names is in
+// ICON_DIR as a square PNG, so no condition silently falls back to text
+func TestConditionIconsShipped(t *testing.T) {
+	for condition, file := range conditionIcons {
+		f, err := os.Open(filepath.Join(ICON_DIR, file))
+		if err != nil {
+			t.Errorf("%s: %v", condition, err)
+			continue
+		}
+		img, err := png.Decode(f)
+		f.Close()
+		if err != nil {
+			t.Errorf("%s: %s is not a PNG: %v", condition, file, err)
+			continue
+		}
+		if bounds := img.Bounds(); bounds.Dx() != bounds.Dy() {
+			t.Errorf("%s: %s is %dx%d, want it square", condition, file, bounds.Dx(), bounds.Dy())
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+const (
+	INFO_ROW_HEIGHT  float32 = 30
+	INFO_MARK_SIZE   float32 = 12
+	FEELS_LIKE_FIELD string  = "feels_like"
+)
+
+// infoField is one row of the info grid on the right of the weather panel.
+// Mark, when set, draws a small glyph in the INFO_MARK_SIZE box at origin,
+// just after the label.
+type infoField struct {
+	Key   string
+	Label func(weather WeatherData) string
+	Mark  func(weather WeatherData, origin rl.Vector2, color rl.Color)
+}
+
+var infoFields = []infoField{
+	{"humidity", func(weather WeatherData) string {
+		return fmt.Sprintf("Humidity: %d%%", weather.Humidity)
+	}, nil},
+	{"wind", func(weather WeatherData) string {
+		if cardinal := windCardinal(weather.WindDeg); cardinal != "" {
+			return fmt.Sprintf("Wind: %s %s", formatWind(weather.WindSpeed, weather.Unit), cardinal)
+		}
+		return fmt.Sprintf("Wind: %s", formatWind(weather.WindSpeed, weather.Unit))
+	}, func(weather WeatherData, origin rl.Vector2, color rl.Color) {
+		drawWindArrow(weather.WindDeg, origin, INFO_MARK_SIZE, color)
+	}},
+	{"pressure", func(weather WeatherData) string {
+		return fmt.Sprintf("Pressure: %d hPa", weather.Pressure)
+	}, func(weather WeatherData, origin rl.Vector2, color rl.Color) {
+		drawTrendArrow(weather.PressureTrend, origin, INFO_MARK_SIZE, color)
+	}},
+	{"visibility", func(weather WeatherData) string {
+		return fmt.Sprintf("Visibility: %s", formatVisibility(weather.Visibility))
+	}, nil},
+}
+
+// formatVisibility shows meters as km with one decimal, or N/A if unknown
+func formatVisibility(meters int) string {
+	if meters == VISIBILITY_UNKNOWN {
+		return "N/A"
+	}
+	return fmt.Sprintf("%.1f km", float64(meters)/1000)
+}
+
+func isInfoField(key string) bool {
+	if key == FEELS_LIKE_FIELD {
+		return true
+	}
+	for _, field := range infoFields {
+		if field.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// hiddenFields reads the comma separated HIDE_FIELDS config value.
+// Unknown names are logged and ignored.
+func hiddenFields() map[string]bool {
+	hidden := make(map[string]bool)
+	for _, key := range strings.Split(os.Getenv("HIDE_FIELDS"), ",") {
+		key = strings.ToLower(strings.TrimSpace(key))
+		if key == "" {
+			continue
+		}
+		if !isInfoField(key) {
+			slog.Warn("HIDE_FIELDS: unknown field", "field", key)
+			continue
+		}
+		hidden[key] = true
+	}
+	return hidden
+}
+
+// drawInfoGrid draws the visible fields top to bottom from origin, so hidden
+// fields leave no gap
+func drawInfoGrid(font rl.Font, theme Theme, weather WeatherData, hidden map[string]bool, origin rl.Vector2) {
+	row := 0
+	for _, field := range infoFields {
+		if hidden[field.Key] {
+			continue
+		}
+
+		label := field.Label(weather)
+		position := rl.NewVector2(origin.X, origin.Y+float32(row)*INFO_ROW_HEIGHT)
+		rl.DrawTextEx(font, label, position, 20, 0, theme.Text)
+
+		if field.Mark != nil {
+			width := rl.MeasureTextEx(font, label, 20, 0).X
+			field.Mark(weather, rl.NewVector2(position.X+width+8, position.Y+4), theme.Text)
+		}
+		row++
+	}
+}
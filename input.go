@@ -0,0 +1,91 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// validInputRune rejects the replacement character and anything that is not a
+// real codepoint, such as lone surrogates from a broken clipboard
+func validInputRune(r rune) bool {
+	return r != utf8.RuneError && utf8.ValidRune(r)
+}
+
+// acceptInputRune decides what typed or pasted characters reach the input
+// box. unicode.IsPrint covers all of printable ASCII, 32 (space) through 126
+// (~), and rejects DEL (127) and the other control characters, while letting
+// accented and other non-ASCII letters through.
+func acceptInputRune(r rune) bool {
+	return unicode.IsPrint(r) && validInputRune(r)
+}
+
+// sanitizeText drops invalid UTF-8 bytes from text before it reaches the
+// input buffer. Valid multibyte characters are kept intact.
+func sanitizeText(text string) []rune {
+	var runes []rune
+	for _, r := range strings.ToValidUTF8(text, "") {
+		if validInputRune(r) {
+			runes = append(runes, r)
+		}
+	}
+	return runes
+}
+
+// editBuffer applies this frame's typing to buffer: printable characters,
+// Ctrl+V, Backspace and Delete, and cursor movement. Keys that mean something
+// different per field, such as Enter or Up and Down, are left to the caller.
+func editBuffer(buffer *inputBuffer) {
+	key := rl.GetCharPressed()
+
+	for key > 0 {
+
+		// ANY PRINTABLE CHARACTER; THE FONT'S RANGES DECIDE WHAT CAN BE DRAWN
+		if acceptInputRune(key) {
+			buffer.Insert(key)
+		}
+
+		key = rl.GetCharPressed()
+	}
+
+	// CTRL+V PASTES, TRUNCATED TO WHATEVER ROOM IS LEFT
+	pasteDown := rl.IsKeyDown(rl.KeyLeftControl) || rl.IsKeyDown(rl.KeyRightControl)
+	if pasteDown && rl.IsKeyPressed(rl.KeyV) {
+		buffer.Append(pasteText(rl.GetClipboardText()))
+	}
+
+	if rl.IsKeyPressed(rl.KeyBackspace) || rl.IsKeyPressedRepeat(rl.KeyBackspace) {
+		buffer.Backspace()
+	}
+	if rl.IsKeyPressed(rl.KeyDelete) || rl.IsKeyPressedRepeat(rl.KeyDelete) {
+		buffer.Delete()
+	}
+
+	// LEFT/RIGHT MOVE THE CURSOR, HOME/END JUMP TO EITHER END
+	if rl.IsKeyPressed(rl.KeyLeft) || rl.IsKeyPressedRepeat(rl.KeyLeft) {
+		buffer.Left()
+	}
+	if rl.IsKeyPressed(rl.KeyRight) || rl.IsKeyPressedRepeat(rl.KeyRight) {
+		buffer.Right()
+	}
+	if rl.IsKeyPressed(rl.KeyHome) {
+		buffer.Home()
+	}
+	if rl.IsKeyPressed(rl.KeyEnd) {
+		buffer.End()
+	}
+}
+
+// pasteText cleans clipboard text for the single-line input box. Newlines,
+// tabs and other non-printable characters are dropped.
+func pasteText(text string) []rune {
+	var runes []rune
+	for _, r := range sanitizeText(text) {
+		if acceptInputRune(r) {
+			runes = append(runes, r)
+		}
+	}
+	return runes
+}
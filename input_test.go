@@ -0,0 +1,57 @@
+package main
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSanitizeText(t *testing.T) {
+	tests := []struct {
+		name, text, want string
+	}{
+		{"plain", "Paris", "Paris"},
+		{"multibyte kept", "Zürich São Paulo", "Zürich São Paulo"},
+		{"invalid bytes", "Par\xffis\xfe", "Paris"},
+		{"lone high surrogate", "Ber\xed\xa0\x80lin", "Berlin"},
+		{"lone low surrogate", "Ber\xed\xb0\x80lin", "Berlin"},
+		{"truncated sequence", "Köln\xc3", "Köln"},
+		{"replacement character", "Oslo�", "Oslo"},
+		{"only garbage", "\xff\xed\xa0\x80", ""},
+	}
+	for _, test := range tests {
+		if got := string(sanitizeText(test.text)); got != test.want {
+			t.Errorf("%s: sanitizeText(%q) = %q, want %q", test.name, test.text, got, test.want)
+		}
+	}
+}
+
+func TestAcceptInputRune(t *testing.T) {
+	tests := []struct {
+		r           rune
+		valid, want bool
+	}{
+		{0, true, false},
+		{'\n', true, false},
+		{31, true, false},
+		{' ', true, true},
+		{'A', true, true},
+		{'~', true, true},
+		{127, true, false},
+		{'é', true, true},
+		{'ß', true, true},
+		{'東', true, true},
+		{utf8.RuneError, false, false},
+		{0xD800, false, false},
+		{0xDFFF, false, false},
+		{utf8.MaxRune + 1, false, false},
+		{-1, false, false},
+	}
+	for _, test := range tests {
+		if got := validInputRune(test.r); got != test.valid {
+			t.Errorf("validInputRune(%U) = %v, want %v", test.r, got, test.valid)
+		}
+		if got := acceptInputRune(test.r); got != test.want {
+			t.Errorf("acceptInputRune(%U) = %v, want %v", test.r, got, test.want)
+		}
+	}
+}
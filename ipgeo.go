@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+)
+
+// IP_GEO_URL_DEFAULT is a free HTTPS service that answers with the caller's
+// approximate location. IP_GEO_URL can point at another with a "city" field.
+const IP_GEO_URL_DEFAULT = "https://ipapi.co/json/"
+
+type ipLocation struct {
+	City   string `json:"city"`
+	Error  bool   `json:"error"`
+	Reason string `json:"reason"`
+}
+
+// isIPGeolocationEnabled reports whether first-launch city detection may
+// run. IP_GEOLOCATION=false turns it off, since it sends the IP to a third party.
+func isIPGeolocationEnabled() bool {
+	value := os.Getenv("IP_GEOLOCATION")
+	if value == "" {
+		return true
+	}
+	enabled, _ := strconv.ParseBool(value)
+	return enabled
+}
+
+// detectCityByIP guesses the user's city from their public IP. client is a
+// parameter so the lookup can be pointed at a fake server.
+func detectCityByIP(ctx context.Context, client *http.Client) (string, error) {
+	geoURL := os.Getenv("IP_GEO_URL")
+	if geoURL == "" {
+		geoURL = IP_GEO_URL_DEFAULT
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, geoURL, nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", requestError("detect city", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", requestError("read response", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("IP lookup error (%d)", resp.StatusCode)
+	}
+
+	var location ipLocation
+	if err := json.Unmarshal(body, &location); err != nil {
+		return "", fmt.Errorf("failed to parse JSON: %v", err)
+	}
+	if location.Error {
+		return "", fmt.Errorf("IP lookup failed: %s", location.Reason)
+	}
+	if location.City == "" {
+		return "", fmt.Errorf("IP lookup returned no city")
+	}
+
+	return location.City, nil
+}
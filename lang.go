@@ -0,0 +1,63 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const LANG_DEFAULT = "en"
+
+// envLang reads the OpenWeather language code from LANG, e.g. "de" or
+// "zh_cn". LANG is also the POSIX locale, so values like "de_DE.UTF-8" are
+// cut back to "de_de" and "C" or "POSIX" mean the default.
+//
+// Descriptions are drawn with the already loaded font, so any character it
+// has no glyph for shows as "?". Non-Latin scripts such as Cyrillic or CJK
+// need FONT_PATH to name a font that covers them.
+func envLang() string {
+	lang := strings.ToLower(os.Getenv("LANG"))
+	lang, _, _ = strings.Cut(lang, ".")
+	lang, _, _ = strings.Cut(lang, "@")
+
+	switch lang {
+	case "", "c", "posix":
+		return LANG_DEFAULT
+	}
+	return lang
+}
+
+// isDefaultLang reports whether lang gets the API's own English text
+func isDefaultLang(lang string) bool {
+	return lang == LANG_DEFAULT || strings.HasPrefix(lang, LANG_DEFAULT+"_")
+}
+
+// conditionText is the condition as drawn: the localized description when a
+// language other than English is set, otherwise the friendly label
+func conditionText(weather WeatherData) string {
+	if weather.Description != "" && !isDefaultLang(envLang()) {
+		return capitalizeFirst(weather.Description)
+	}
+	return conditionLabel(weather.Condition)
+}
+
+// conditionSubtitle is the longer description drawn under the condition,
+// e.g. "Light intensity shower rain". It is empty when there is none or when
+// conditionText already shows it.
+func conditionSubtitle(weather WeatherData) string {
+	if weather.Description == "" || !isDefaultLang(envLang()) {
+		return ""
+	}
+	return capitalizeFirst(weather.Description)
+}
+
+// capitalizeFirst upper-cases the first letter; the API sends descriptions
+// in lower case
+func capitalizeFirst(text string) string {
+	r, size := utf8.DecodeRuneInString(text)
+	if r == utf8.RuneError {
+		return text
+	}
+	return string(unicode.ToUpper(r)) + text[size:]
+}
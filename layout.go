@@ -0,0 +1,93 @@
+package main
+
+import rl "github.com/gen2brain/raylib-go/raylib"
+
+// Layout holds the position of every top-level UI element for a window size.
+// It is plain math with no raylib calls, so it can be checked without a window.
+type Layout struct {
+	Tabs       rl.Vector2
+	Badge      rl.Vector2
+	Summary    rl.Vector2
+	Units      rl.Vector2
+	Updated    rl.Vector2
+	Backoff    rl.Vector2
+	Title      rl.Vector2
+	TextBox    rl.Rectangle
+	Search     rl.Rectangle
+	Hint       rl.Vector2
+	InputChars rl.Vector2
+	InputText  rl.Vector2
+	Status     rl.Vector2
+	Spinner    rl.Vector2
+	NoData     rl.Vector2
+	Panel      rl.Rectangle
+	Footer     rl.Vector2
+	Debug      rl.Rectangle
+	Favorites  rl.Rectangle
+	AddPanel   rl.Rectangle
+	DropPanel  rl.Rectangle
+	Settings   rl.Rectangle
+}
+
+// PanelLayout positions the contents of the weather panel relative to it
+type PanelLayout struct {
+	Location      rl.Vector2
+	Temperature   rl.Vector2
+	Condition     rl.Vector2
+	ConditionIcon rl.Vector2
+	Description   rl.Vector2
+	IconCaption   rl.Vector2
+	TempRange     rl.Vector2
+	FeelsLike     rl.Vector2
+	LocalTime     rl.Vector2
+	SunTimes      rl.Vector2
+	InfoGrid      rl.Vector2
+	Sparkline     rl.Rectangle
+}
+
+func computeLayout(width, height int32) Layout {
+	w, h := float32(width), float32(height)
+	centerX := w / 2
+
+	return Layout{
+		Tabs:       rl.NewVector2(10, 10),
+		Badge:      rl.NewVector2(w-150, 10),
+		Summary:    rl.NewVector2(10, 28),
+		Units:      rl.NewVector2(w-150, 28),
+		Updated:    rl.NewVector2(w-150, 46),
+		Backoff:    rl.NewVector2(w-150, 64),
+		Title:      rl.NewVector2(centerX-120, 50),
+		TextBox:    rl.NewRectangle(centerX-175, 80, 350, 50),
+		Search:     rl.NewRectangle(centerX+185, 80, 100, 50),
+		Hint:       rl.NewVector2(centerX-130, 135),
+		InputChars: rl.NewVector2(centerX-85, 155),
+		InputText:  rl.NewVector2(centerX-85, 180),
+		Status:     rl.NewVector2(centerX-85, 200),
+		Spinner:    rl.NewVector2(centerX-100, 208),
+		NoData:     rl.NewVector2(centerX-130, 240),
+		Panel:      rl.NewRectangle(50, 220, w-100, h-250),
+		Footer:     rl.NewVector2(10, h-25),
+		Debug:      rl.NewRectangle(20, 20, w-40, h-40),
+		Favorites:  rl.NewRectangle(10, 60, 150, FAVORITES_MAX*FAVORITES_ROW_HEIGHT),
+		AddPanel:   rl.NewRectangle(w-112, 190, 28, 24),
+		DropPanel:  rl.NewRectangle(w-78, 190, 28, 24),
+		Settings:   rl.NewRectangle(w-44, 90, 30, 30),
+	}
+}
+
+func computePanelLayout(panel rl.Rectangle) PanelLayout {
+	return PanelLayout{
+		Location:      rl.NewVector2(panel.X+20, panel.Y+20),
+		Temperature:   rl.NewVector2(panel.X+20, panel.Y+60),
+		Condition:     rl.NewVector2(panel.X+150, panel.Y+70),
+		ConditionIcon: rl.NewVector2(panel.X+150, panel.Y+55),
+		Description:   rl.NewVector2(panel.X+150, panel.Y+96),
+		IconCaption:   rl.NewVector2(panel.X+206, panel.Y+72),
+		TempRange:     rl.NewVector2(panel.X+20, panel.Y+110),
+		FeelsLike:     rl.NewVector2(panel.X+20, panel.Y+132),
+		LocalTime:     rl.NewVector2(panel.X+20, panel.Y+154),
+		SunTimes:      rl.NewVector2(panel.X+20, panel.Y+176),
+		InfoGrid:      rl.NewVector2(panel.X+panel.Width/2, panel.Y+20),
+		Sparkline:     rl.NewRectangle(panel.X+panel.Width/2, panel.Y+150, panel.Width/2-20, 36),
+	}
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+func TestComputeLayout(t *testing.T) {
+	tests := []struct {
+		width, height int32
+		textBox       rl.Rectangle
+		search        rl.Rectangle
+		panel         rl.Rectangle
+		settings      rl.Rectangle
+		addPanel      rl.Rectangle
+		debug         rl.Rectangle
+		footer        rl.Vector2
+		badge         rl.Vector2
+	}{
+		{
+			800, 450,
+			rl.NewRectangle(225, 80, 350, 50),
+			rl.NewRectangle(585, 80, 100, 50),
+			rl.NewRectangle(50, 220, 700, 200),
+			rl.NewRectangle(756, 90, 30, 30),
+			rl.NewRectangle(688, 190, 28, 24),
+			rl.NewRectangle(20, 20, 760, 410),
+			rl.NewVector2(10, 425),
+			rl.NewVector2(650, 10),
+		},
+		{
+			700, 450,
+			rl.NewRectangle(175, 80, 350, 50),
+			rl.NewRectangle(535, 80, 100, 50),
+			rl.NewRectangle(50, 220, 600, 200),
+			rl.NewRectangle(656, 90, 30, 30),
+			rl.NewRectangle(588, 190, 28, 24),
+			rl.NewRectangle(20, 20, 660, 410),
+			rl.NewVector2(10, 425),
+			rl.NewVector2(550, 10),
+		},
+		{
+			1280, 720,
+			rl.NewRectangle(465, 80, 350, 50),
+			rl.NewRectangle(825, 80, 100, 50),
+			rl.NewRectangle(50, 220, 1180, 470),
+			rl.NewRectangle(1236, 90, 30, 30),
+			rl.NewRectangle(1168, 190, 28, 24),
+			rl.NewRectangle(20, 20, 1240, 680),
+			rl.NewVector2(10, 695),
+			rl.NewVector2(1130, 10),
+		},
+	}
+
+	for _, test := range tests {
+		layout := computeLayout(test.width, test.height)
+
+		rects := []struct {
+			name      string
+			got, want rl.Rectangle
+		}{
+			{"TextBox", layout.TextBox, test.textBox},
+			{"Search", layout.Search, test.search},
+			{"Panel", layout.Panel, test.panel},
+			{"Settings", layout.Settings, test.settings},
+			{"AddPanel", layout.AddPanel, test.addPanel},
+			{"Debug", layout.Debug, test.debug},
+		}
+		for _, rect := range rects {
+			if rect.got != rect.want {
+				t.Errorf("%dx%d: %s = %v, want %v", test.width, test.height, rect.name, rect.got, rect.want)
+			}
+		}
+		if layout.Footer != test.footer || layout.Badge != test.badge {
+			t.Errorf("%dx%d: Footer, Badge = %v, %v; want %v, %v", test.width, test.height, layout.Footer, layout.Badge, test.footer, test.badge)
+		}
+	}
+}
+
+// overlaps reports whether two rectangles share any area
+func overlaps(a, b rl.Rectangle) bool {
+	return a.X < b.X+b.Width && b.X < a.X+a.Width && a.Y < b.Y+b.Height && b.Y < a.Y+a.Height
+}
+
+// TestLayoutControlsApart checks the clickable controls never overlap, from
+// the minimum window size up
+func TestLayoutControlsApart(t *testing.T) {
+	for _, size := range [][2]int32{{700, 450}, {800, 450}, {1280, 720}, {1920, 1080}} {
+		layout := computeLayout(size[0], size[1])
+		controls := map[string]rl.Rectangle{
+			"TextBox":   layout.TextBox,
+			"Search":    layout.Search,
+			"Favorites": layout.Favorites,
+			"AddPanel":  layout.AddPanel,
+			"DropPanel": layout.DropPanel,
+			"Settings":  layout.Settings,
+			"Panel":     layout.Panel,
+		}
+		for aName, a := range controls {
+			for bName, b := range controls {
+				if aName < bName && overlaps(a, b) {
+					t.Errorf("%dx%d: %s %v overlaps %s %v", size[0], size[1], aName, a, bName, b)
+				}
+			}
+		}
+	}
+}
+
+func TestComputePanelLayout(t *testing.T) {
+	panel := computePanelLayout(rl.NewRectangle(50, 220, 700, 200))
+
+	vectors := []struct {
+		name      string
+		got, want rl.Vector2
+	}{
+		{"Location", panel.Location, rl.NewVector2(70, 240)},
+		{"Temperature", panel.Temperature, rl.NewVector2(70, 280)},
+		{"Description", panel.Description, rl.NewVector2(200, 316)},
+		{"SunTimes", panel.SunTimes, rl.NewVector2(70, 396)},
+		{"InfoGrid", panel.InfoGrid, rl.NewVector2(400, 240)},
+	}
+	for _, v := range vectors {
+		if v.got != v.want {
+			t.Errorf("%s = %v, want %v", v.name, v.got, v.want)
+		}
+	}
+	if want := rl.NewRectangle(400, 370, 330, 36); panel.Sparkline != want {
+		t.Errorf("Sparkline = %v, want %v", panel.Sparkline, want)
+	}
+}
+
+func TestPanelGrid(t *testing.T) {
+	area := rl.NewRectangle(50, 220, 700, 200)
+
+	if cells := panelGrid(area, 1); len(cells) != 1 || cells[0] != area {
+		t.Errorf("one panel = %v, want the whole area", cells)
+	}
+
+	cells := panelGrid(area, 2)
+	want := []rl.Rectangle{rl.NewRectangle(50, 220, 345, 200), rl.NewRectangle(405, 220, 345, 200)}
+	if len(cells) != 2 || cells[0] != want[0] || cells[1] != want[1] {
+		t.Errorf("two panels = %v, want %v", cells, want)
+	}
+
+	cells = panelGrid(area, MAX_PANELS)
+	last := cells[len(cells)-1]
+	if right := last.X + last.Width; math.Abs(float64(right-(area.X+area.Width))) > 0.01 {
+		t.Errorf("last cell ends at %v, want %v", right, area.X+area.Width)
+	}
+}
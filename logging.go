@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+)
+
+// THE LOG FILE LIVES NEXT TO THE DISK CACHE. ONCE IT GROWS PAST LOG_MAX_SIZE
+// IT IS MOVED TO go-weather.log.1 AT STARTUP, SO AT MOST TWO FILES ARE KEPT.
+const (
+	LOG_FILE     string = "go-weather.log"
+	LOG_MAX_SIZE int64  = 1 << 20
+)
+
+// setupLogging routes slog and the standard log package to stderr and the
+// log file, at the level named by LOG_LEVEL (info by default). Any string
+// containing the API key is redacted before it is written.
+func setupLogging() {
+	var level slog.Level
+	var levelErr error
+	if value := os.Getenv("LOG_LEVEL"); value != "" {
+		levelErr = level.UnmarshalText([]byte(value))
+	}
+
+	var out io.Writer = os.Stderr
+	file, fileErr := openLogFile()
+	if fileErr == nil {
+		out = io.MultiWriter(os.Stderr, file)
+	}
+
+	handler := slog.NewTextHandler(out, &slog.HandlerOptions{
+		Level: level,
+		ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+			if attr.Value.Kind() == slog.KindString {
+				attr.Value = slog.StringValue(redactAPIKey(attr.Value.String()))
+			} else if err, ok := attr.Value.Any().(error); ok {
+				attr.Value = slog.StringValue(redactAPIKey(err.Error()))
+			}
+			return attr
+		},
+	})
+	slog.SetDefault(slog.New(handler))
+
+	if levelErr != nil {
+		slog.Warn("LOG_LEVEL not recognised, using info", "value", os.Getenv("LOG_LEVEL"))
+	}
+	if fileErr != nil {
+		slog.Warn("Logging to stderr only", "err", fileErr)
+	}
+}
+
+// openLogFile opens the log file for appending, rotating it first if it has
+// grown past LOG_MAX_SIZE
+func openLogFile() (*os.File, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate cache dir: %v", err)
+	}
+	dir = filepath.Join(dir, "go-weather")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create log dir: %v", err)
+	}
+
+	path := filepath.Join(dir, LOG_FILE)
+	if info, err := os.Stat(path); err == nil && info.Size() > LOG_MAX_SIZE {
+		os.Rename(path, path+".1")
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log file: %v", err)
+	}
+	return file, nil
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// useTempCacheDir points os.UserCacheDir at a fresh directory and returns
+// where the log file goes inside it
+func useTempCacheDir(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", dir)
+	t.Setenv("HOME", dir)
+	t.Setenv("LocalAppData", dir)
+
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return filepath.Join(cacheDir, "go-weather", LOG_FILE)
+}
+
+func TestSetupLoggingRedactsKey(t *testing.T) {
+	path := useTempCacheDir(t)
+	t.Setenv("API_KEY", "secret-key-123")
+	saved := slog.Default()
+	t.Cleanup(func() { slog.SetDefault(saved) })
+
+	setupLogging()
+	slog.Info("Fetching", "url", "https://api.example.com/weather?appid=secret-key-123&q=Oslo")
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if log := string(data); strings.Contains(log, "secret-key-123") || !strings.Contains(log, REDACTED_API_KEY) {
+		t.Errorf("log file = %q, want the key redacted", log)
+	}
+}
+
+func TestOpenLogFileRotates(t *testing.T) {
+	path := useTempCacheDir(t)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, make([]byte, LOG_MAX_SIZE+1), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := openLogFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+
+	if info, err := os.Stat(path); err != nil || info.Size() != 0 {
+		t.Errorf("log file after rotation: %v, %v; want a fresh empty file", info, err)
+	}
+	if info, err := os.Stat(path + ".1"); err != nil || info.Size() != LOG_MAX_SIZE+1 {
+		t.Errorf("rotated file: %v, %v; want the old log", info, err)
+	}
+}
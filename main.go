@@ -1,12 +1,17 @@
 package main
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
+	"flag"
 	"fmt"
-	"io"
 	"log"
-	"net/http"
+	"log/slog"
 	"os"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	rl "github.com/gen2brain/raylib-go/raylib"
@@ -14,189 +19,1043 @@ import (
 )
 
 func init() {
-	err := godotenv.Load(".env")
-	if err != nil {
-		log.Fatal("Error loading .env file")
+	// PREFER config.json; WITHOUT ONE, .env IS A CONVENIENCE AND THE REAL
+	// ENVIRONMENT MAY ALREADY HAVE THE KEYS
+	config, err := loadConfig()
+	switch {
+	case err == nil:
+		config.Apply()
+	case errors.Is(err, os.ErrNotExist):
+		if err := godotenv.Load(".env"); err != nil {
+			log.Printf("no .env file loaded (%v), using the environment", err)
+		}
+	default:
+		log.Fatalf("Config error: %v", err)
 	}
-}
 
-type WeatherData struct {
-	Location    string
-	Temperature int
-	Condition   string
-	Humidity    int
-	WindSpeed   float32
-	FeelsLike   int
+	httpClient = newHTTPClient()
+	cacheTTL = envDuration("CACHE_TTL", CACHE_TTL_DEFAULT)
+	retryAttempts = envInt("API_RETRIES", API_RETRIES_DEFAULT)
+	windUnit = envWindUnit()
+	clockLayout = envClockLayout()
+	feelsLikeDelta = envInt("FEELS_LIKE_DELTA", FEELS_LIKE_DELTA_DEFAULT)
 }
 
-// FETCH WEATHER DATA FUNCTION
-func fetchWeatherData(cityName string) (WeatherData, error) {
-	var weather WeatherData
+func main() {
+	// LOGGING OPENS THE LOG FILE, SO IT STARTS HERE RATHER THAN IN init,
+	// WHICH ALSO RUNS UNDER go test
+	setupLogging()
+
+	cityFlag := flag.String("city", "", "city to look up")
+	asciiFlag := flag.Bool("ascii", false, "print the weather for -city as ASCII art and exit")
+	noGUIFlag := flag.Bool("no-gui", false, "print the weather for -city as text and exit")
+	jsonFlag := flag.Bool("json", false, "print the weather for -city as JSON and exit")
+	logFileFlag := flag.String("log-file", CSV_LOG_FILE_DEFAULT, "CSV file each successful fetch is appended to, empty to disable")
+	offlineFlag := flag.Bool("offline", false, "serve only cached data and never touch the network")
+	widgetFlag := flag.Bool("widget", false, "start as the small pinned widget, like WIDGET_MODE=true")
+	metricsAddrFlag := flag.String("metrics-addr", "", "run headless, refetching -city and PRELOAD_CITIES, and serve Prometheus metrics on this address, e.g. :9090")
+	flag.Parse()
 
-	apiKey := os.Getenv("API_KEY")
-	apiURL := os.Getenv("API_URL")
+	// THE CITY CAN ALSO BE GIVEN AS TRAILING ARGUMENTS, e.g. go-weather New York
+	city := strings.TrimSpace(*cityFlag)
+	if city == "" {
+		city = strings.TrimSpace(strings.Join(flag.Args(), " "))
+	}
 
-	url := fmt.Sprintf("%s?q=%s&appid=%s&units=metric", apiURL, cityName, apiKey)
+	loadConditionLabels()
+	weatherLog := newCSVLog(*logFileFlag)
 
-	resp, err := http.Get(url)
-	if err != nil {
-		return weather, fmt.Errorf("failed to fetch weather: %v", err)
+	// THE CONTEXT IS CANCELLED WHEN THE WINDOW CLOSES, WHICH ABORTS ANY
+	// REQUEST STILL IN FLIGHT
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	headless := *asciiFlag || *noGUIFlag || *jsonFlag || *metricsAddrFlag != ""
+
+	// SETTINGS SAVED FROM THE SETTINGS OVERLAY OVERRIDE THE CONFIG IN THE GUI
+	state := loadState()
+	if !headless {
+		applySettings(state)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return weather, fmt.Errorf("API error (%d): %s", resp.StatusCode, string(body))
+	// OPEN-METEO IS KEYLESS; OPENWEATHERMAP NEEDS ITS KEY AND ENDPOINT. THIS
+	// IS CHECKED HERE RATHER THAN IN init, SO TESTS RUN WITHOUT A KEY.
+	if envProvider() == PROVIDER_OPENWEATHERMAP {
+		for _, key := range []string{"API_KEY", "API_URL"} {
+			if os.Getenv(key) == "" {
+				log.Fatalf("%s is not set; add it to .env or the environment", key)
+			}
+		}
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	// ONE PROVIDER FROM CONFIG; THE GUI ASKS FOR A COPY IN THE CURRENT UNIT
+	provider, err := newProvider(envUnit())
 	if err != nil {
-		return weather, fmt.Errorf("failed to read response: %v", err)
+		fmt.Fprintf(os.Stderr, "Config error: %v\n", err)
+		os.Exit(2)
 	}
 
-	var apiResp map[string]interface{}
-	if err := json.Unmarshal(body, &apiResp); err != nil {
-		return weather, fmt.Errorf("failed to parse JSON: %v", err)
+	// INIT CACHE BACKEND, MEMORY UNLESS CONFIGURED OTHERWISE. OFFLINE MODE
+	// DEFAULTS TO DISK, SINCE A FRESH MEMORY CACHE HAS NOTHING TO SERVE.
+	cacheBackend := os.Getenv("CACHE_BACKEND")
+	if *offlineFlag && cacheBackend == "" {
+		cacheBackend = "disk"
 	}
-
-	weather = WeatherData{
-		Location:    apiResp["name"].(string),
-		Temperature: int(apiResp["main"].(map[string]interface{})["temp"].(float64)),
-		FeelsLike:   int(apiResp["main"].(map[string]interface{})["feels_like"].(float64)),
-		Humidity:    int(apiResp["main"].(map[string]interface{})["humidity"].(float64)),
-		WindSpeed:   float32(apiResp["wind"].(map[string]interface{})["speed"].(float64)),
+	cache, err := newCache(cacheBackend)
+	if err != nil {
+		slog.Warn("Cache backend unavailable, using memory", "err", err)
+		cache = newMemoryCache()
 	}
 
-	return weather, nil
-}
+	// HEADLESS MODES PRINT TO THE TERMINAL AND NEVER OPEN A WINDOW
+	if headless {
+		if city == "" {
+			city = strings.TrimSpace(os.Getenv("DEFAULT_CITY"))
+		}
+		if city == "" {
+			fmt.Fprintln(os.Stderr, "Enter a city name: -ascii, -no-gui, -json and -metrics-addr require a city")
+			os.Exit(2)
+		}
 
-func main() {
+		source := provider
+		if *offlineFlag {
+			source = offlineProvider{cache: cache, unit: envUnit()}
+		}
+
+		// WITH -metrics-addr, KEEP FETCHING AND SERVE THE READINGS AS A
+		// SCRAPE TARGET UNTIL INTERRUPTED
+		if *metricsAddrFlag != "" {
+			if *offlineFlag {
+				weatherLog = nil
+			}
+			interval := envDuration("REFRESH_INTERVAL", REFRESH_INTERVAL_DEFAULT)
+			if err := runMetrics(ctx, *metricsAddrFlag, source, metricsCities(city), interval, weatherLog); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+
+		weather, err := source.Fetch(ctx, city)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if !*offlineFlag {
+			if err := weatherLog.Append(weather, time.Now()); err != nil {
+				slog.Warn("Weather not logged", "err", err)
+			}
+		}
+
+		switch {
+		case *jsonFlag:
+			data, err := marshalWeather(weather)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println(string(data))
+		case *asciiFlag:
+			printASCII(os.Stdout, weather)
+		default:
+			printWeather(os.Stdout, weather)
+		}
+		return
+	}
 
 	const (
 		WIDTH           int32  = 800
 		HEIGHT          int32  = 450
-		FPS             int32  = 60
+		MIN_WIDTH       int32  = 700
+		MIN_HEIGHT      int32  = 450
 		MAX_INPUT_CHARS int    = 18
 		FONT_PATH       string = "resource/static/JetBrainsMono-Regular.ttf"
 	)
 
 	var (
-		name            = make([]rune, MAX_INPUT_CHARS+1)
-		letterCount     int
+		input           = newInputBuffer(MAX_INPUT_CHARS)
 		framesCounter   int
-		mouseOnText     bool
+		inputFocused    bool
 		textBox         rl.Rectangle
-		inputText       string
-		statusMessage   string
-		statusColor     rl.Color
-		statusClearTime time.Time
-		weather         WeatherData
-		lastFetchTime   time.Time
+		status          statusLine
+		panels          = []*cityPanel{{}}
+		active          int
+		panel           = panels[active]
 		fetchCooldown   = 2 * time.Second
+		widgetMode      = *widgetFlag || isWidgetMode()
+		dragAnchor      rl.Vector2
+		preload         *preloadProgress
+		showForecast    bool
+		forecast        []ForecastEntry
+		forecastCity    string
+		forecastPending bool
+		forecastResults = make(chan forecastResult, 1)
+		hidden          = hiddenFields()
+		minimalNetwork  = isMinimalNetwork()
+		staleAfter      = envDuration("STALE_AFTER", STALE_AFTER_DEFAULT)
+		debugMode       = isDebugMode()
+		showDebug       bool
+		debugScroll     float32
+		summaryAlways   = isSummaryAlways()
+		splashEnabled   = isSplashEnabled()
+		reduceMotion    = isReduceMotion()
+		fetchResults    = make(chan panelResult, 1)
+		unit            = envUnit()
+		particles       particleSystem
+		autoRefresh     bool
+		refreshAt       time.Time
+		refreshInterval = envDuration("REFRESH_INTERVAL", REFRESH_INTERVAL_DEFAULT)
+		jitter          = refreshJitter()
+		backoff         refreshBackoff
+		history         = newSearchHistory(state.History)
+		sparkline       sparklineHistory
+		toasts          toastQueue
+		fullscreen      fullscreenToggle
+		showSettings    bool
+		editingFavorite = -1
+		labelInput      = newInputBuffer(FAVORITE_LABEL_MAX_CHARS)
+		offline         = *offlineFlag
 	)
 
-	rl.InitWindow(WIDTH, HEIGHT, "Go Weather")
+	// source is the provider in the current unit, or the cache alone while
+	// offline so that no request goes out
+	source := func() Provider {
+		if offline {
+			return offlineProvider{cache: cache, unit: unit}
+		}
+		return provider.WithUnits(unit)
+	}
+
+	windowWidth, windowHeight := WIDTH, HEIGHT
+	if widgetMode {
+		rl.SetConfigFlags(rl.FlagWindowUndecorated | rl.FlagWindowTopmost)
+		windowWidth, windowHeight = WIDGET_WIDTH, WIDGET_HEIGHT
+	} else {
+		rl.SetConfigFlags(rl.FlagWindowResizable)
+	}
+
+	rl.InitWindow(windowWidth, windowHeight, "Go Weather")
 	defer rl.CloseWindow()
 
-	rl.SetTargetFPS(FPS)
+	// BELOW THIS THE INPUT ROW RUNS INTO THE FAVORITES LIST AND THE PANEL
+	// CANNOT FIT ITS ROWS
+	if !widgetMode {
+		rl.SetWindowMinSize(int(MIN_WIDTH), int(MIN_HEIGHT))
+	}
+
+	// THE TARGET FPS DROPS WHILE NOTHING HAPPENS, TO SAVE CPU WHEN IDLE
+	frames := newFrameRate(time.Now())
 
-	font := rl.LoadFontEx(FONT_PATH, 48, nil)
+	// EVERY DRAW AND MEASURE CALL USES THIS ONE FONT INSTANCE. IT IS LOADED
+	// ONCE AND OUTLIVES FULLSCREEN TOGGLES, WHICH KEEP THE GL CONTEXT.
+	font := loadFont(FONT_PATH, 48)
 	defer rl.UnloadFont(font)
 
-	rl.SetTextureFilter(font.Texture, rl.FilterBilinear)
+	// CONDITION ICONS ARE OPTIONAL; ANY THAT ARE MISSING DRAW AS TEXT
+	icons := loadIcons(ICON_DIR)
+	defer icons.Unload()
+
+	// LOAD A CUSTOM PALETTE OVER THE DEFAULT THEME IF ONE EXISTS. THE FILE
+	// APPLIES TO WHICHEVER PRESET IS ACTIVE.
+	themePath := os.Getenv("THEME_FILE")
+	if themePath == "" {
+		themePath = THEME_FILE
+	}
+	loadTheme := func(dark bool) Theme {
+		loadedTheme, err := loadThemeFile(themePath, presetTheme(dark))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			slog.Warn("Theme not loaded", "err", err)
+		}
+		return loadedTheme
+	}
+	theme := loadTheme(state.DarkMode)
+
+	// SHOW THE SPLASH WHILE STARTUP WORK RUNS
+	if splashEnabled {
+		presentSplash(font, theme)
+	}
 
-	//  INIT TEXTBOX RECTANGLE
-	textBox = rl.NewRectangle(225, 80, 350, 50)
+	//  INIT LAYOUT AND TEXTBOX RECTANGLE, RECOMPUTED EACH FRAME FOR RESIZES
+	layout := computeLayout(WIDTH, HEIGHT)
+	textBox = layout.TextBox
 
-	for !rl.WindowShouldClose() {
+	// startPreload fetches cities into the cache in the background. The
+	// goroutines get their own copy of the provider, since the loop reassigns
+	// provider and unit when settings change.
+	startPreload := func(cities []string) *preloadProgress {
+		p := provider.WithUnits(unit)
+		return preloadCities(ctx, cities, cache, func(city string) (WeatherData, error) {
+			return p.Fetch(ctx, city)
+		})
+	}
 
-		// UPDATE
-		if rl.CheckCollisionPointRec(rl.GetMousePosition(), textBox) {
-			mouseOnText = true
+	// PRELOAD CONFIGURED CITIES INTO THE CACHE
+	if cities := preloadList(); len(cities) > 0 && !minimalNetwork && !offline {
+		preload = startPreload(cities)
+	}
+
+	// fetchAsync always goes to the API, on a goroutine, and leaves the result
+	// on fetchResults tagged with the panel it is for
+	fetchAsync := func(target *cityPanel, city string) {
+		status.Set("Fetching...", theme.Info, time.Now())
+		target.Pending = true
+
+		// WITH THE FORECAST SHOWN, FETCH BOTH SO THEY UPDATE TOGETHER
+		fetchPanel(ctx, fetchResults, target, provider.WithUnits(unit), city, showForecast && target == panel, weatherLog)
+	}
+
+	// fetchInto serves city to target from the cache, or fetches it. Offline,
+	// an expired entry is served too and a miss is reported instead.
+	fetchInto := func(target *cityPanel, city string) {
+		if cachedWeather, ok := cache.Get(cacheKey(city, unit)); ok {
+			target.Query = city
+			target.Weather = cachedWeather
+			status.Set("Data fetched successfully! (cached)", theme.Success, time.Now())
+		} else if offline {
+			cachedWeather, err := source().Fetch(ctx, city)
+			if err != nil {
+				toasts.Push(fmt.Sprintf("Offline: %v", err), theme.Caution, time.Now())
+				return
+			}
+			target.Query = city
+			target.Weather = cachedWeather
+			status.Set("Showing cached data (offline)", theme.Caution, time.Now())
 		} else {
-			mouseOnText = false
+			fetchAsync(target, city)
 		}
+	}
 
-		if mouseOnText {
+	// startFetch fetches city into the active panel
+	startFetch := func(city string) {
+		fetchInto(panel, city)
+	}
 
-			rl.SetMouseCursor(rl.MouseCursorIBeam)
+	// searchReady reports whether a search may start now. Enter and the
+	// Search button share it, and a pending fetch or the cooldown blocks both.
+	searchReady := func() bool {
+		return canSearch(input.String(), panel, time.Now(), fetchCooldown)
+	}
+
+	// saveStateAsync writes the current state off the render loop. Shutdown
+	// waits on pendingSaves so the last change is not lost, and saveFailed
+	// tells it whether the last write went through.
+	var (
+		pendingSaves sync.WaitGroup
+		saveFailed   atomic.Bool
+	)
+	saveStateAsync := func() {
+		pendingSaves.Add(1)
+		go func(saved appState) {
+			defer pendingSaves.Done()
+			if err := saveState(saved); err != nil {
+				slog.Error("State not saved", "err", err)
+				saveFailed.Store(true)
+			} else {
+				saveFailed.Store(false)
+			}
+		}(state.clone())
+	}
 
-			key := rl.GetCharPressed()
+	// CITY NAME SUGGESTIONS FROM THE GEOCODING API, AND A CHOICE OF PLACES
+	// WHEN A SEARCHED NAME MATCHES SEVERAL, SHARING ONE GEOCODE CACHE
+	geocoder := newGeocodeCache(provider.Geocode)
+	suggester := newSuggester(geocoder)
+	picker := newCityPicker(geocoder)
 
-			for key > 0 {
+	// submitSearch records the input in the history and fetches it
+	submitSearch := func() {
+		query := strings.TrimSpace(input.String())
+		suggester.Commit(query)
+		if minimalNetwork || offline {
+			picker.Clear()
+		} else {
+			picker.Check(ctx, query)
+		}
+		history.Add(query)
+		state.History = history.Entries()
+		saveStateAsync()
+		startFetch(query)
+	}
 
-				if key >= 32 && key <= 125 && letterCount < MAX_INPUT_CHARS {
+	// setInput replaces the input box contents. Text put there by the app is
+	// not offered as suggestions.
+	setInput := func(text string) {
+		input.Set(text)
+		suggester.Commit(input.String())
+	}
 
-					name[letterCount] = rune(key)
-					letterCount++
+	// selectPanel makes panels[index] the one the input box searches for
+	selectPanel := func(index int) {
+		active = index
+		panel = panels[active]
+		setInput(panel.Weather.Location)
+	}
 
-					if letterCount < len(name) {
-						name[letterCount] = 0
-					}
-				}
+	// refetchAll refreshes every panel, from the cache when it can
+	refetchAll := func() {
+		for _, p := range panels {
+			if p.Query != "" && !p.Pending {
+				fetchInto(p, p.Query)
+			}
+		}
+	}
+
+	// SETTINGS CHANGE FROM HOTKEYS AND THE SETTINGS OVERLAY ALIKE, AND EVERY
+	// CHANGE IS SAVED SO THE NEXT LAUNCH STARTS THE SAME WAY
+
+	// switchUnit toggles Celsius/Fahrenheit and refetches in the new unit
+	switchUnit := func() {
+		unit = unit.Toggle()
+		state.Units = unit.APIParam()
+		saveStateAsync()
+		forecastCity = ""
+		refetchAll()
+	}
+
+	// switchWind cycles the wind unit. It is display only, so nothing is
+	// refetched.
+	switchWind := func() {
+		windUnit = windUnit.Next(unit)
+		state.WindUnit = windUnit.Param()
+		saveStateAsync()
+		status.Set(fmt.Sprintf("Wind in %s", windUnit.Symbol()), theme.Success, time.Now())
+	}
+
+	switchTheme := func() {
+		state.DarkMode = !state.DarkMode
+		theme = loadTheme(state.DarkMode)
+		saveStateAsync()
+	}
+
+	// switchAutoRefresh turns auto-refresh on or off, which minimal network
+	// mode does not allow
+	switchAutoRefresh := func() {
+		if minimalNetwork {
+			status.Set("Auto-refresh is off in minimal network mode", theme.Warn, time.Now())
+			return
+		}
+		autoRefresh = !autoRefresh
+		state.AutoRefresh = autoRefresh
+		saveStateAsync()
+		backoff.Success()
+		refreshAt = nextRefresh(time.Now(), refreshInterval, jitter)
+		if autoRefresh {
+			status.Set(fmt.Sprintf("Auto-refresh every %v", refreshInterval), theme.Success, time.Now())
+		} else {
+			status.Set("Auto-refresh off", theme.Success, time.Now())
+		}
+	}
+
+	// stepRefresh moves the auto-refresh interval by steps minutes
+	stepRefresh := func(steps int) {
+		refreshInterval = stepInterval(refreshInterval, steps)
+		state.RefreshInterval = refreshInterval.String()
+		saveStateAsync()
+		refreshAt = nextRefresh(time.Now(), refreshInterval, jitter)
+	}
+
+	// switchLang moves to the next description language. The provider reads
+	// LANG when it is built, so it is rebuilt, and every panel is fetched
+	// again past the cache, which holds text in the old language.
+	switchLang := func() {
+		lang := nextLang(envLang())
+		os.Setenv("LANG", lang)
+		state.Lang = lang
+		saveStateAsync()
+
+		rebuilt, err := newProvider(unit)
+		if err != nil {
+			toasts.Push(fmt.Sprintf("Error: %v", err), theme.Warn, time.Now())
+			return
+		}
+		provider = rebuilt
+		forecastCity = ""
+		for _, p := range panels {
+			if p.Query != "" && !p.Pending && !offline {
+				fetchAsync(p, p.Query)
+			}
+		}
+	}
+
+	// settingsRows describes the settings overlay for the current values
+	settingsRows := func() []settingsRow {
+		themeName := "Light"
+		if state.DarkMode {
+			themeName = "Dark"
+		}
+		refresh := "Off"
+		if autoRefresh {
+			refresh = "On"
+		}
+		return []settingsRow{
+			{Label: "Units", Value: unit.Symbol(), Increase: SettingUnits},
+			{Label: "Wind", Value: windUnit.Resolve(unit).Symbol(), Increase: SettingWind},
+			{Label: "Theme", Value: themeName, Increase: SettingTheme},
+			{Label: "Auto-refresh", Value: refresh, Increase: SettingAutoRefresh},
+			{Label: "Refresh every", Value: fmt.Sprintf("%dm", int(refreshInterval.Minutes())), Increase: SettingIntervalUp, Decrease: SettingIntervalDown},
+			{Label: "Language", Value: strings.ToUpper(envLang()), Increase: SettingLang},
+		}
+	}
+
+	// AUTO-REFRESH PICKS UP WHERE THE LAST SESSION LEFT IT
+	if state.AutoRefresh && !minimalNetwork {
+		autoRefresh = true
+		refreshAt = nextRefresh(time.Now(), refreshInterval, jitter)
+	}
+
+	// A CITY FROM THE COMMAND LINE WINS, OTHERWISE PICK UP WHERE THE LAST
+	// SESSION LEFT OFF
+	if city == "" {
+		city = state.LastCity
+	}
+	if city == "" {
+		city = os.Getenv("DEFAULT_CITY")
+	}
+	if !widgetMode && city != "" {
+		setInput(city)
+		startFetch(input.String())
+	}
+
+	// WIDGET MODE HAS NO INPUT BOX, SO IT SHOWS THE DEFAULT CITY. IT IS
+	// FETCHED LIKE ANY OTHER, SO THE WINDOW DRAWS WHILE IT IS IN FLIGHT.
+	if widgetMode {
+		if city := os.Getenv("DEFAULT_CITY"); city != "" {
+			startFetch(city)
+		}
+	}
+
+	// WITH NOTHING TO GO ON, GUESS THE CITY FROM THE IP WITHOUT HOLDING UP
+	// THE WINDOW. A FAILED GUESS JUST LEAVES THE INPUT EMPTY.
+	detectedCity := make(chan string, 1)
+	if !widgetMode && city == "" && !minimalNetwork && !offline && isIPGeolocationEnabled() {
+		go func() {
+			detected, err := detectCityByIP(ctx, httpClient)
+			if err != nil {
+				slog.Warn("City detection failed", "err", err)
+				return
+			}
+			detectedCity <- detected
+		}()
+	}
+
+	background := newBackgroundFade(theme.Background)
 
-				key = rl.GetCharPressed()
+	splashFadeEnd := time.Now().Add(SPLASH_FADE)
+
+	confirmingClose := false
+	for {
+		// CLOSING ASKS FIRST IF THE LAST STATE SAVE FAILED. raylib RAISES THE
+		// CLOSE REQUEST FOR ONE FRAME AT A TIME, SO IT CAN BE TURNED DOWN.
+		if rl.WindowShouldClose() {
+			pendingSaves.Wait()
+			if !saveFailed.Load() {
+				break
+			}
+			confirmingClose = true
+			inputFocused = false
+		}
+		if confirmingClose {
+			choice := closeChoice(confirmBounds(float32(rl.GetScreenWidth()), float32(rl.GetScreenHeight())))
+			if choice == CloseSave {
+				err := saveState(state)
+				if err == nil {
+					break
+				}
+				toasts.Push(fmt.Sprintf("Save failed: %v", err), theme.Warn, time.Now())
+			}
+			if choice == CloseDiscard {
+				break
 			}
+			if choice == CloseCancel {
+				confirmingClose = false
+			}
+		}
 
-			if rl.IsKeyPressed(rl.KeyBackspace) {
-				letterCount--
-				if letterCount < 0 {
-					letterCount = 0
+		if widgetMode {
+			// THE WIDGET HAS NO STATUS LINE OR TOASTS, SO A FAILED FETCH IS
+			// ONLY LOGGED AND THE PLACEHOLDER STAYS UP
+			select {
+			case result := <-fetchResults:
+				applyResult(result, cache, time.Now())
+				if result.err != nil {
+					slog.Error("Widget fetch failed", "err", result.err)
 				}
-				name[letterCount] = 0
+			default:
+			}
+
+			dragWidget(&dragAnchor)
+			frames.Update(time.Now(), panel.Pending || (splashEnabled && time.Now().Before(splashFadeEnd)))
+
+			rl.BeginDrawing()
+			rl.ClearBackground(theme.Background)
+			drawWidget(font, theme, panel.Weather)
+			if splashEnabled {
+				drawSplash(font, theme, splashAlpha(splashFadeEnd, reduceMotion))
 			}
+			rl.EndDrawing()
+			continue
+		}
+
+		// REFLOW FOR THE CURRENT WINDOW SIZE
+		layout = computeLayout(int32(rl.GetScreenWidth()), int32(rl.GetScreenHeight()))
+		textBox = layout.TextBox
+
+		// UPDATE
+		// A CLICK ON A TOAST DISMISSES IT
+		toasts.Dismiss(float32(rl.GetScreenWidth()), float32(rl.GetScreenHeight()))
+
+		// THE SETTINGS OVERLAY IS MODAL. WHILE IT IS OPEN, INCLUDING THE FRAME
+		// A CLICK OUTSIDE CLOSES IT, CLICKS DO NOT REACH THE UI BEHIND IT.
+		// S OR THE GEAR OPENS IT; S, ESCAPE OR A CLICK OUTSIDE CLOSES IT.
+		overlayOpen := showSettings || confirmingClose
+		settingsKey := !inputFocused && editingFavorite < 0 && rl.IsKeyPressed(rl.KeyS) &&
+			!rl.IsKeyDown(rl.KeyLeftControl) && !rl.IsKeyDown(rl.KeyRightControl)
+		switch {
+		case confirmingClose:
+			// THE CLOSE PROMPT TAKES THE CLICKS, SO THE SETTINGS WAIT FOR IT
+		case showSettings:
+			rows := settingsRows()
+			switch settingsClicked(settingsBounds(float32(rl.GetScreenWidth()), float32(rl.GetScreenHeight()), len(rows)), rows) {
+			case SettingUnits:
+				switchUnit()
+			case SettingWind:
+				switchWind()
+			case SettingTheme:
+				switchTheme()
+			case SettingAutoRefresh:
+				switchAutoRefresh()
+			case SettingIntervalDown:
+				stepRefresh(-1)
+			case SettingIntervalUp:
+				stepRefresh(1)
+			case SettingLang:
+				switchLang()
+			case SettingClose:
+				showSettings = false
+			}
+			if settingsKey || rl.IsKeyPressed(rl.KeyEscape) {
+				showSettings = false
+			}
+		case settingsKey || buttonClicked(layout.Settings):
+			showSettings = true
+			inputFocused = false
+			suggester.Commit(input.String())
+		}
+
+		// FOCUS STICKS UNTIL A CLICK ELSEWHERE OR ESCAPE. ENTER FOCUSES THE
+		// BOX, AND ONLY SEARCHES ONCE IT IS FOCUSED.
+		mouseOnText := rl.CheckCollisionPointRec(rl.GetMousePosition(), textBox)
+		if rl.IsMouseButtonPressed(rl.MouseLeftButton) && !overlayOpen {
+			inputFocused = mouseOnText
+		}
+		searchKey := inputFocused && rl.IsKeyPressed(rl.KeyEnter)
+		if !inputFocused && !showSettings && editingFavorite < 0 && rl.IsKeyPressed(rl.KeyEnter) {
+			inputFocused = true
+		}
+		if inputFocused && rl.IsKeyPressed(rl.KeyEscape) {
+			setInput("")
+			inputFocused = false
+		}
+
+		// RIGHT-CLICKING A FAVORITE EDITS ITS LABEL IN PLACE. ENTER SAVES, AN
+		// EMPTY LABEL GOES BACK TO THE CITY NAME, AND ESCAPE OR A CLICK
+		// ELSEWHERE CANCELS.
+		if !overlayOpen {
+			if index := rightClickedFavorite(state.Favorites, layout.Favorites); index >= 0 {
+				editingFavorite = index
+				inputFocused = false
+				labelInput.Set(state.Labels[normalizeCity(state.Favorites[index])])
+			} else if rl.IsMouseButtonPressed(rl.MouseLeftButton) {
+				editingFavorite = -1
+			}
+		}
+		if editingFavorite >= len(state.Favorites) {
+			editingFavorite = -1
+		}
+		if editingFavorite >= 0 {
+			switch {
+			case rl.IsKeyPressed(rl.KeyEnter):
+				state.Labels = setFavoriteLabel(state.Labels, state.Favorites[editingFavorite], labelInput.String())
+				saveStateAsync()
+				editingFavorite = -1
+			case rl.IsKeyPressed(rl.KeyEscape):
+				editingFavorite = -1
+			default:
+				editBuffer(labelInput)
+			}
+		}
 
+		// typing is true while keys go to a text field, which turns the
+		// single-key shortcuts off
+		typing := inputFocused || editingFavorite >= 0
+
+		// ESCAPE CLEARS THE BOX OR CANCELS A LABEL WHILE TYPING, OR CLOSES THE
+		// SETTINGS, INSTEAD OF QUITTING
+		if typing || showSettings || confirmingClose {
+			rl.SetExitKey(rl.KeyNull)
 		} else {
-			rl.SetMouseCursor(rl.MouseCursorDefault)
+			rl.SetExitKey(rl.KeyEscape)
 		}
 
 		if mouseOnText {
+			rl.SetMouseCursor(rl.MouseCursorIBeam)
+		} else {
+			rl.SetMouseCursor(rl.MouseCursorDefault)
+		}
+
+		if inputFocused {
+			editBuffer(input)
+
+			// UP/DOWN RECALL PAST SEARCHES
+			if rl.IsKeyPressed(rl.KeyUp) {
+				if recalled, ok := history.Older(input.String()); ok {
+					setInput(recalled)
+				}
+			}
+			if rl.IsKeyPressed(rl.KeyDown) {
+				if recalled, ok := history.Newer(); ok {
+					setInput(recalled)
+				}
+			}
+		}
+
+		if inputFocused {
 			framesCounter++
 		} else {
 			framesCounter = 0
 		}
 
-		// FETCH WEATHER DATA
-		if rl.IsKeyPressed(rl.KeyEnter) && inputText != "" && time.Since(lastFetchTime) > fetchCooldown {
-			statusMessage = "Fetching..."
-			statusColor = rl.Blue
-			fetchedWeather, err := fetchWeatherData(inputText)
+		// FETCH WEATHER DATA ON A GOROUTINE SO RENDERING KEEPS GOING.
+		// SEARCHES ARE IGNORED WHILE A FETCH IS PENDING SO REQUESTS NEVER OVERLAP.
+		// BLANK INPUT IS CAUGHT HERE RATHER THAN SENT TO THE API
+		if searchKey || (!overlayOpen && buttonClicked(layout.Search)) {
+			if strings.TrimSpace(input.String()) == "" {
+				status.Set("Enter a city name", theme.Caution, time.Now())
+			} else if searchReady() {
+				submitSearch()
+			}
+		}
+
+		// SUGGEST CITIES AS THE USER TYPES. A CLICK OR TAB TAKES ONE, SO TAB
+		// ONLY TOGGLES THE FORECAST WHILE NO SUGGESTIONS ARE SHOWN.
+		if !minimalNetwork && !offline {
+			suggester.Update(ctx, input.String(), time.Now())
+		}
+		suggestion := -1
+		if !overlayOpen {
+			suggestion = clickedSuggestion(suggester.Suggestions, textBox)
+		}
+		tabTakesSuggestion := len(suggester.Suggestions) > 0 && rl.IsKeyPressed(rl.KeyTab)
+		if tabTakesSuggestion {
+			suggestion = 0
+		}
+
+		// A CHOSEN PLACE IS FETCHED BY ITS COORDINATES, SINCE EVEN
+		// "Springfield,US" NAMES SEVERAL
+		picker.Update(input.String())
+		if !overlayOpen && !panel.Pending {
+			if choice := picker.Clicked(textBox); choice >= 0 {
+				place := picker.Candidates[choice]
+				picker.Clear()
+				setInput(place.Query())
+				startFetch(formatCoords(place.Lat, place.Lon))
+			}
+		}
+		if suggestion >= 0 && !panel.Pending {
+			setInput(suggester.Suggestions[suggestion].Query())
+			submitSearch()
+		}
+
+		// TOGGLE CELSIUS/FAHRENHEIT AND REFETCH THE SHOWN CITY IN THE NEW UNIT
+		if !typing && rl.IsKeyPressed(rl.KeyF) {
+			switchUnit()
+		}
+
+		// W CYCLES THE WIND UNIT
+		if !typing && rl.IsKeyPressed(rl.KeyW) {
+			switchWind()
+		}
+
+		// FILL IN THE DETECTED CITY UNLESS THE USER GOT THERE FIRST
+		select {
+		case detected := <-detectedCity:
+			if input.Len() == 0 && panel.Weather.Location == "" && !panel.Pending {
+				setInput(detected)
+				startFetch(input.String())
+			}
+		default:
+		}
+
+		// D SWITCHES BETWEEN THE LIGHT AND DARK THEMES
+		if !typing && rl.IsKeyPressed(rl.KeyD) {
+			switchTheme()
+		}
+
+		// H HIDES THE INSTRUCTION LINES AROUND THE INPUT BOX
+		if !typing && rl.IsKeyPressed(rl.KeyH) {
+			state.HideHelpers = !state.HideHelpers
+			saveStateAsync()
+		}
+
+		// R TOGGLES AUTO-REFRESH
+		if !typing && rl.IsKeyPressed(rl.KeyR) {
+			switchAutoRefresh()
+		}
+
+		// O TAKES THE APP OFFLINE AND BACK. OFFLINE, SEARCHES ARE ANSWERED FROM
+		// THE CACHE ONLY AND NOTHING IN THE BACKGROUND TOUCHES THE NETWORK.
+		if !typing && rl.IsKeyPressed(rl.KeyO) {
+			offline = !offline
+			if offline {
+				suggester.Commit(input.String())
+				showForecast = false
+				status.Set("Offline - showing cached data only", theme.Caution, time.Now())
+			} else {
+				refreshAt = nextRefresh(time.Now(), refreshInterval, jitter)
+				status.Set("Back online", theme.Success, time.Now())
+			}
+		}
+
+		// A FETCHES EVERY FAVORITE INTO THE CACHE, e.g. BEFORE GOING OFFLINE,
+		// SPACED AND CAPPED LIKE THE STARTUP PRELOAD. ONE BATCH RUNS AT A TIME
+		// AND REPORTS ITS SUMMARY WHEN IT ENDS.
+		if !typing && rl.IsKeyPressed(rl.KeyA) {
+			switch {
+			case offline:
+				status.Set("Offline - favorites not updated", theme.Caution, time.Now())
+			case len(state.Favorites) == 0:
+				status.Set("No favorites to update", theme.Caution, time.Now())
+			case preload == nil:
+				preload = startPreload(slices.Clone(state.Favorites))
+			}
+		}
+
+		// REFRESH EVERY PANEL WHEN DUE, SKIPPING THE CACHE. THE COOLDOWN KEEPS
+		// A REFRESH FROM LANDING RIGHT ON TOP OF A MANUAL FETCH.
+		if autoRefresh && !offline && time.Now().After(refreshAt) {
+			for _, p := range panels {
+				if p.Query != "" && !p.Pending && cooldownOver(p.LastFetch, time.Now(), fetchCooldown) {
+					fetchAsync(p, p.Query)
+				}
+			}
+		}
+
+		// COLLECT A FINISHED FETCH WITHOUT BLOCKING THE FRAME
+		select {
+		case result := <-fetchResults:
+			applyResult(result, cache, time.Now())
+
+			var rateLimited *RateLimitError
+			errors.As(result.err, &rateLimited)
+
+			// FAILURES STRETCH THE NEXT REFRESH; A SUCCESS RESETS IT
+			if autoRefresh {
+				if result.err == nil {
+					backoff.Success()
+				} else {
+					backoff.Failure()
+				}
+				refreshAt = nextRefresh(time.Now(), backoff.Interval(refreshInterval), jitter)
+
+				// NEVER REFRESH BEFORE THE API SAYS WE MAY
+				if rateLimited != nil {
+					if allowedAt := time.Now().Add(rateLimited.Wait()); allowedAt.After(refreshAt) {
+						refreshAt = allowedAt
+					}
+				}
+			}
+			if result.err == nil {
+				if result.withForecast {
+					forecast = result.forecast
+					forecastCity = result.city
+				}
+				status.Set("Data fetched successfully!", theme.Success, time.Now())
+				if temperatureLooksWrong(result.weather.Unit.ToCelsius(result.weather.Temperature)) {
+					status.Set(fmt.Sprintf("%s looks wrong, check the API units setting", formatTemperature(result.weather.Temperature, result.weather.Unit)), theme.Caution, time.Now())
+				}
+
+				// REMEMBER THE CITY FOR NEXT LAUNCH, WRITTEN OFF THE RENDER LOOP
+				if result.city != state.LastCity {
+					state.LastCity = result.city
+					saveStateAsync()
+				}
+			} else {
+				// ERRORS GO TO TOASTS, SO DROP THE "Fetching..." LINE
+				status.Clear()
+				if rateLimited != nil {
+					toasts.Push(fmt.Sprintf("Rate limited - try again in %v", rateLimited.Wait().Round(time.Second)), theme.Caution, time.Now())
+				} else if errors.Is(result.err, ErrCityNotFound) {
+					toasts.Push("City not found - check spelling", theme.Warn, time.Now())
+				} else {
+					toasts.Push(fmt.Sprintf("Error: %v", result.err), theme.Warn, time.Now())
+				}
+			}
+		default:
+		}
+
+		// "+" ADDS AN EMPTY PANEL READY FOR A SEARCH, "-" DROPS THE ACTIVE ONE,
+		// AND CLICKING A PANEL IN THE GRID MAKES IT ACTIVE
+		if !overlayOpen && buttonClicked(layout.AddPanel) && len(panels) < MAX_PANELS {
+			panels = append(panels, &cityPanel{})
+			selectPanel(len(panels) - 1)
+		}
+		if !overlayOpen && buttonClicked(layout.DropPanel) && len(panels) > 1 {
+			panels, active = removePanel(panels, active)
+			selectPanel(active)
+		}
+		if len(panels) > 1 && !showForecast && !overlayOpen {
+			if clicked := clickedPanel(panelGrid(layout.Panel, len(panels))); clicked >= 0 && clicked != active {
+				selectPanel(clicked)
+			}
+		}
+
+		// CTRL+S STARS THE SHOWN CITY
+		ctrlDown := rl.IsKeyDown(rl.KeyLeftControl) || rl.IsKeyDown(rl.KeyRightControl)
+		if ctrlDown && rl.IsKeyPressed(rl.KeyS) && panel.Weather.Location != "" {
+			if favorites, added := addFavorite(state.Favorites, panel.Weather.Location); added {
+				state.Favorites = favorites
+				saveStateAsync()
+				status.Set(fmt.Sprintf("Added %s to favorites", panel.Weather.Location), theme.Success, time.Now())
+			} else if len(state.Favorites) >= FAVORITES_MAX {
+				status.Set(fmt.Sprintf("Favorites are full (%d max)", FAVORITES_MAX), theme.Warn, time.Now())
+			}
+		}
+
+		// 1-9 OR A CLICK SWITCHES TO A FAVORITE. DIGITS TYPE INTO THE BOX
+		// WHILE IT HAS FOCUS, SO THE HOTKEYS ONLY WORK OUTSIDE IT.
+		favorite := -1
+		if !overlayOpen {
+			favorite = clickedFavorite(state.Favorites, layout.Favorites)
+		}
+		if favorite < 0 && !typing {
+			favorite = favoriteKey(state.Favorites)
+		}
+		if favorite >= 0 && !panel.Pending {
+			setInput(state.Favorites[favorite])
+			startFetch(input.String())
+		}
+
+		// COPY WEATHER AS JSON
+		if !typing && rl.IsKeyPressed(rl.KeyJ) && panel.Weather.Location != "" {
+			data, err := marshalWeather(panel.Weather)
 			if err == nil {
-				weather = fetchedWeather
-				statusMessage = "Data fetched successfully!"
-				statusColor = rl.Green
-				lastFetchTime = time.Now()
+				rl.SetClipboardText(string(data))
+				status.Set("Copied JSON", theme.Success, time.Now())
 			} else {
-				statusMessage = fmt.Sprintf("Error: %v", err)
-				statusColor = rl.Red
+				toasts.Push(fmt.Sprintf("Error: %v", err), theme.Warn, time.Now())
+			}
+		}
+
+		// COPY WEATHER AS A ONE-LINE SUMMARY
+		if !typing && rl.IsKeyPressed(rl.KeyC) && panel.Weather.Location != "" {
+			rl.SetClipboardText(formatClipboard(panel.Weather))
+			status.Set("Copied!", theme.Success, time.Now())
+		}
+
+		// TOGGLE FULLSCREEN. THE WINDOW IS NOT HIGH-DPI, SO SCREEN UNITS STAY
+		// PIXELS AND THE REFLOW ABOVE PICKS UP THE NEW SIZE NEXT FRAME. THE FONT
+		// IS BAKED AT 48PX, ABOVE EVERY UI SIZE, SO TEXT ONLY EVER SCALES DOWN.
+		if rl.IsKeyPressed(rl.KeyF11) {
+			fullscreen.Toggle()
+		}
+
+		// TOGGLE RAW RESPONSE PANEL IN DEBUG MODE
+		if debugMode && rl.IsKeyPressed(rl.KeyF12) {
+			showDebug = !showDebug
+			debugScroll = 0
+		}
+
+		if showDebug {
+			debugScroll = scrollDebugPanel(debugScroll, layout.Debug)
+		}
+
+		// TOGGLE BETWEEN CURRENT AND FORECAST VIEWS
+		if rl.IsKeyPressed(rl.KeyTab) && !tabTakesSuggestion && panel.Weather.Location != "" && !minimalNetwork && !offline {
+			showForecast = !showForecast
+		}
+
+		// FETCH FORECAST LAZILY THE FIRST TIME IT IS SHOWN FOR A CITY. IT RUNS
+		// ON A GOROUTINE LIKE ANY OTHER FETCH, AND THE VIEW SAYS IT IS LOADING
+		// UNTIL THE RESULT ARRIVES.
+		if showForecast && !forecastPending && forecastCity != panel.Query && cooldownOver(panel.LastFetch, time.Now(), fetchCooldown) {
+			forecastPending = true
+			panel.LastFetch = time.Now()
+			go func(city string, unit Unit, provider Provider) {
+				fetched, err := provider.Forecast(ctx, city)
+				select {
+				case forecastResults <- forecastResult{city: city, unit: unit, forecast: fetched, err: err}:
+				case <-ctx.Done():
+				}
+			}(panel.Query, unit, source())
+		}
+
+		// A FORECAST IN A UNIT SWITCHED AWAY FROM IS DROPPED AND FETCHED AGAIN
+		select {
+		case result := <-forecastResults:
+			forecastPending = false
+			switch {
+			case result.err == nil && result.unit == unit:
+				forecast = result.forecast
+				forecastCity = result.city
+			case result.err != nil && result.city == panel.Query:
+				showForecast = false
+				toasts.Push(fmt.Sprintf("Error: %v", result.err), theme.Warn, time.Now())
 			}
-			statusClearTime = time.Now().Add(3 * time.Second)
+		default:
 		}
 
-		if statusMessage != "" && time.Now().After(statusClearTime) {
-			statusMessage = ""
+		// REPORT PRELOAD RESULT ONCE IT COMPLETES
+		if preload != nil && preload.finished() {
+			color := theme.Success
+			if preload.failed.Load() > 0 {
+				color = theme.Caution
+			}
+			status.Set(preload.summary(), color, time.Now())
+			preload = nil
+		}
+
+		status.Expire(time.Now(), panel.Pending)
+		toasts.Expire(time.Now())
+
+		// STAY AT THE FULL FRAME RATE WHILE ANYTHING MOVES: A FETCH SPINNER,
+		// PARTICLES, A BACKGROUND OR SPLASH FADE, OR THE BLINKING CARET
+		busy := typing || preload != nil || forecastPending || background.Fading(time.Now()) ||
+			(splashEnabled && time.Now().Before(splashFadeEnd)) ||
+			(!reduceMotion && hasParticles(panel.Weather.Condition))
+		for _, p := range panels {
+			busy = busy || p.Pending
 		}
+		frames.Update(time.Now(), busy)
 
 		// BEGIN DRAW
 		rl.BeginDrawing()
 
-		rl.ClearBackground(rl.RayWhite)
+		// TINT THE BACKGROUND BY CONDITION, FADING BETWEEN TINTS
+		now := time.Now()
+		if panel.Weather.Location == "" {
+			background.Target(theme.Background, now)
+		} else {
+			background.Target(conditionBackground(panel.Weather.Condition, theme), now)
+		}
+		rl.ClearBackground(background.Color(now))
 
-		rl.DrawTextEx(
-			font,
-			"PLACE MOUSE OVER INPUT BOX!",
-			rl.NewVector2(280, 50), 20, 0, rl.Gray,
-		)
+		if !state.HideHelpers {
+			rl.DrawTextEx(
+				font,
+				"CLICK THE INPUT BOX TO TYPE!",
+				layout.Title, 20, 0, theme.Muted,
+			)
+		}
 
-		rl.DrawRectangleRec(textBox, rl.LightGray)
+		rl.DrawRectangleRec(textBox, theme.InputBox)
 
-		if mouseOnText {
+		if inputFocused {
 			rl.DrawRectangleLines(
 				int32(textBox.X),
 				int32(textBox.Y),
 				int32(textBox.Width),
 				int32(textBox.Height),
-				rl.Red,
+				theme.Warn,
 			)
 		} else {
 			rl.DrawRectangleLines(
@@ -204,121 +1063,199 @@ func main() {
 				int32(textBox.Y),
 				int32(textBox.Width),
 				int32(textBox.Height),
-				rl.DarkGray,
+				theme.Text,
 			)
 		}
 
-		// CONVERT RUNES TO STRING BEFORE DRAWING
-		inputText = string(name[:letterCount])
+		inputText := input.String()
 		rl.DrawTextEx(
 			font,
 			inputText,
-			rl.NewVector2(textBox.X+5, textBox.Y+8), 40, 0, rl.Maroon,
+			rl.NewVector2(textBox.X+5, textBox.Y+8), 40, 0, theme.Input,
 		)
 
-		rl.DrawTextEx(
-			font,
-			fmt.Sprintf("INPUT CHARS: %d/%d", letterCount, MAX_INPUT_CHARS),
-			rl.NewVector2(315, 155), 20, 0, rl.DarkGray,
-		)
+		drawButton(font, theme, layout.Search, "Search", searchReady())
+		drawCooldownBar(theme, layout.Search, cooldownRemaining(panel.LastFetch, time.Now(), fetchCooldown))
+		drawFavorites(font, theme, state.Favorites, state.Labels, layout.Favorites, editingFavorite, labelInput)
+		drawGear(theme, layout.Settings)
+		drawButton(font, theme, layout.AddPanel, "+", len(panels) < MAX_PANELS)
+		drawButton(font, theme, layout.DropPanel, "-", len(panels) > 1)
 
-		rl.DrawTextEx(
-			font,
-			fmt.Sprintf("INPUT TEXT: %s", inputText),
-			rl.NewVector2(315, 180), 20, 0, rl.DarkGray,
-		)
+		if !state.HideHelpers {
+			rl.DrawTextEx(
+				font,
+				fmt.Sprintf("INPUT CHARS: %d/%d", input.Len(), input.Max()),
+				layout.InputChars, 20, 0, theme.Text,
+			)
+
+			rl.DrawTextEx(
+				font,
+				fmt.Sprintf("INPUT TEXT: %s", inputText),
+				layout.InputText, 20, 0, theme.Text,
+			)
+		}
 
-		if statusMessage != "" {
+		// ONE-LINE SUMMARY FOR NARROW WINDOWS, OR ALWAYS IF CONFIGURED
+		if panel.Weather.Location != "" && (summaryAlways || int32(rl.GetScreenWidth()) < NARROW_WIDTH) {
+			screenWidth := float32(rl.GetScreenWidth())
 			rl.DrawTextEx(
 				font,
-				statusMessage,
-				rl.NewVector2(315, 200), 16, 0, statusColor,
+				truncateText(font, formatSummary(panel.Weather), 16, screenWidth-layout.Summary.X*2),
+				layout.Summary, 16, 0, theme.Text,
 			)
 		}
 
+		// THE UNIT IS SHOWN EVEN BEFORE ANY CITY IS FETCHED
 		rl.DrawTextEx(
 			font,
-			"Press ENTER to fetch weather",
-			rl.NewVector2(270, 135), 16, 0, rl.DarkGray,
+			fmt.Sprintf("Units: %s (F)", unit.Symbol()),
+			layout.Units, 16, 0, theme.Muted,
 		)
 
-		if mouseOnText {
-
-			if letterCount < MAX_INPUT_CHARS {
-
-				// DRAW BLINKING UNDERSCORE CHAR_
-				if ((framesCounter / 20) % 2) == 0 {
-					textWidth := rl.MeasureTextEx(font, inputText, 40, 0).X
-
-					rl.DrawTextEx(
-						font,
-						"_",
-						rl.NewVector2(textBox.X+8+textWidth, textBox.Y+12), 40, 0, rl.Maroon,
-					)
-				}
-
+		if panel.Weather.Location != "" {
+			updated := fmt.Sprintf("Updated %s", formatTime(panel.Weather.FetchedAt))
+			if autoRefresh {
+				updated += " (auto)"
 			}
+			rl.DrawTextEx(font, updated, layout.Updated, 16, 0, theme.Muted)
+		}
 
-			// else {
-			// 	rl.DrawTextEx(
-			// 		font,
-			// 		"Press BACKSPACE to delete chars...",
-			// 		rl.NewVector2(230, 180), 20, 0, rl.Gray,
-			// 	)
-			// }
+		// AFTER FAILED REFRESHES THE INTERVAL IS STRETCHED, SO SAY WHEN THE
+		// NEXT TRY IS DUE
+		if autoRefresh && !offline && backoff.BackedOff() {
+			rl.DrawTextEx(font, fmt.Sprintf("Retry at %s", formatTime(refreshAt)), layout.Backoff, 16, 0, theme.Caution)
 		}
 
-		// DRAW WEATHER UI
-		// if no weather data
-		if weather.Location == "" {
+		// THE OFFLINE BANNER IS WIDER THAN THE BADGE SLOT, SO IT IS RIGHT-ALIGNED
+		if offline {
+			const OFFLINE_BANNER = "OFFLINE (CACHED DATA)"
+			bannerWidth := rl.MeasureTextEx(font, OFFLINE_BANNER, 16, 0).X
 			rl.DrawTextEx(
 				font,
-				"No weather data available",
-				rl.NewVector2(270, 240), 20, 0, rl.DarkGray,
+				OFFLINE_BANNER,
+				rl.NewVector2(float32(rl.GetScreenWidth())-bannerWidth-10, layout.Badge.Y), 16, 0, theme.Caution,
 			)
-		} else {
-
-			weatherBox := rl.NewRectangle(50, 220, 700, 200)
-			rl.DrawRectangleRec(weatherBox, rl.NewColor(240, 240, 240, 255))
-			rl.DrawRectangleLinesEx(weatherBox, 2, rl.DarkGray)
-
+		} else if minimalNetwork {
 			rl.DrawTextEx(
 				font,
-				weather.Location,
-				rl.NewVector2(70, 240), 32, 0, rl.DarkBlue,
+				"MINIMAL NETWORK",
+				layout.Badge, 16, 0, theme.Muted,
 			)
+		}
 
+		if preload != nil {
 			rl.DrawTextEx(
 				font,
-				fmt.Sprintf("%d°C", weather.Temperature),
-				rl.NewVector2(70, 280), 48, 0, rl.Black,
+				fmt.Sprintf("Updating cities %d/%d...", preload.done.Load(), preload.total),
+				layout.Footer, 16, 0, theme.Muted,
 			)
+		}
 
+		if status.Message != "" {
 			rl.DrawTextEx(
 				font,
-				weather.Condition,
-				rl.NewVector2(200, 290), 24, 0, rl.DarkGray,
+				status.Message,
+				layout.Status, 16, 0, status.Color,
 			)
+		}
 
-			rl.DrawTextEx(
-				font,
-				fmt.Sprintf("Feels like: %d°C", weather.FeelsLike),
-				rl.NewVector2(70, 340), 18, 0, rl.Gray,
-			)
+		if panel.Pending || forecastPending {
+			drawSpinner(layout.Spinner, status.Color)
+		}
 
+		if !state.HideHelpers {
 			rl.DrawTextEx(
 				font,
-				fmt.Sprintf("Humidity: %d%%", weather.Humidity),
-				rl.NewVector2(400, 240), 20, 0, rl.DarkGray,
+				"Press ENTER to fetch weather",
+				layout.Hint, 16, 0, theme.Text,
 			)
+		}
+
+		if inputFocused {
+
+			// DRAW BLINKING CARET AT THE CURSOR
+			if ((framesCounter / 20) % 2) == 0 {
+				cursorX := rl.MeasureTextEx(font, string([]rune(inputText)[:input.Cursor()]), 40, 0).X
+
+				rl.DrawRectangleRec(
+					rl.NewRectangle(textBox.X+5+cursorX, textBox.Y+8, 2, textBox.Height-16),
+					theme.Input,
+				)
+			}
+
+			// else {
+			// 	rl.DrawTextEx(
+			// 		font,
+			// 		"Press BACKSPACE to delete chars...",
+			// 		rl.NewVector2(230, 180), 20, 0, rl.Gray,
+			// 	)
+			// }
+		}
 
+		// DRAW WEATHER UI. THE FORECAST IS FOR THE ACTIVE PANEL; OTHERWISE
+		// SEVERAL PANELS SHARE THE AREA AS A GRID
+		if showForecast && panel.Weather.Location != "" {
+			drawViewTabs(font, theme, layout.Tabs, showForecast)
+
+			if forecastCity == panel.Query {
+				drawForecast(font, theme, forecast, layout.Panel)
+			} else {
+				drawForecast(font, theme, nil, layout.Panel)
+			}
+		} else if len(panels) > 1 {
+			for i, cell := range panelGrid(layout.Panel, len(panels)) {
+				drawPanelCard(font, theme, panels[i], state.Labels, cell, i == active)
+			}
+		} else if panel.Weather.Location == "" {
 			rl.DrawTextEx(
 				font,
-				fmt.Sprintf("Wind: %.1f km/h", weather.WindSpeed),
-				rl.NewVector2(400, 270), 20, 0, rl.DarkGray,
+				"No weather data available",
+				layout.NoData, 20, 0, theme.Text,
 			)
+		} else {
+			drawViewTabs(font, theme, layout.Tabs, showForecast)
+			drawWeatherPanel(font, theme, icons, withLabel(panel.Weather, state.Labels), hidden, layout.Panel)
+			drawSparkline(font, theme, sparkline.For(weatherLog, panel.Weather), panel.Weather.Unit, computePanelLayout(layout.Panel).Sparkline)
+
+			// GRAY OUT DATA OLDER THAN THE STALE THRESHOLD
+			if age := time.Since(panel.Weather.FetchedAt); age > staleAfter {
+				drawStaleOverlay(font, theme, age, layout.Panel)
+			}
+		}
+
+		drawSuggestions(font, theme, suggester.Suggestions, textBox)
+		drawCityPicker(font, theme, picker.Candidates, textBox)
+
+		// RAIN AND SNOW FALL OVER THE SCENE, UNLESS MOTION IS REDUCED
+		if !reduceMotion {
+			particles.Update(panel.Weather.Condition, float32(rl.GetScreenWidth()), float32(rl.GetScreenHeight()), rl.GetFrameTime())
+			particles.Draw(rl.Fade(theme.Accent, 0.5))
+		}
+
+		if showDebug {
+			drawDebugPanel(font, theme, layout.Debug, debugScroll)
+		}
+
+		if showSettings {
+			rows := settingsRows()
+			drawSettings(font, theme, settingsBounds(float32(rl.GetScreenWidth()), float32(rl.GetScreenHeight()), len(rows)), rows)
+		}
+
+		if confirmingClose {
+			drawConfirm(font, theme, confirmBounds(float32(rl.GetScreenWidth()), float32(rl.GetScreenHeight())))
+		}
+
+		toasts.Draw(font, theme, float32(rl.GetScreenWidth()), float32(rl.GetScreenHeight()))
+
+		// FADE THE SPLASH INTO THE UI
+		if splashEnabled {
+			drawSplash(font, theme, splashAlpha(splashFadeEnd, reduceMotion))
 		}
 
 		rl.EndDrawing()
 	}
+
+	// SHUT DOWN: ABORT BACKGROUND REQUESTS, THEN LET STATE SAVES FINISH
+	cancel()
+	pendingSaves.Wait()
 }
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeProvider answers from memory after a short random delay, so fetches
+// finish out of order. "Atlantis" is never found.
+type fakeProvider struct {
+	unit Unit
+}
+
+func (p fakeProvider) Fetch(ctx context.Context, city string) (WeatherData, error) {
+	time.Sleep(time.Duration(rand.Intn(2000)) * time.Microsecond)
+	if city == "Atlantis" {
+		return WeatherData{}, ErrCityNotFound
+	}
+	return WeatherData{Location: city, Temperature: len(city), Unit: p.unit, FetchedAt: time.Now()}, nil
+}
+
+func (p fakeProvider) Forecast(ctx context.Context, city string) ([]ForecastEntry, error) {
+	time.Sleep(time.Duration(rand.Intn(2000)) * time.Microsecond)
+	return []ForecastEntry{{Temperature: len(city), Unit: p.unit}}, nil
+}
+
+func (p fakeProvider) Geocode(ctx context.Context, query string) ([]GeoLocation, error) {
+	return []GeoLocation{{Name: query}}, nil
+}
+
+func (p fakeProvider) WithUnits(unit Unit) Provider {
+	return fakeProvider{unit: unit}
+}
+
+// TestConcurrentFetches drives the fetch path the way the render loop does:
+// panels fetch on goroutines while the cache is read and written from
+// others and a preload runs. Run it with -race.
+func TestConcurrentFetches(t *testing.T) {
+	const FETCHES = 200
+	cities := []string{"Paris", "Lyon", "Oslo", "Atlantis", "Kyoto", "Lima"}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cache := newMemoryCache()
+	results := make(chan panelResult, 1)
+	panels := []*cityPanel{{}, {}, {}}
+	provider := Provider(fakeProvider{})
+
+	// CACHE READERS AND A PRELOAD RUN THE WHOLE TIME
+	done := make(chan struct{})
+	var readers sync.WaitGroup
+	for range 4 {
+		readers.Add(1)
+		go func() {
+			defer readers.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				city := cities[rand.Intn(len(cities))]
+				cache.Get(cacheKey(city, Celsius))
+				cache.Peek(cacheKey(city, Celsius))
+				time.Sleep(50 * time.Microsecond)
+			}
+		}()
+	}
+	preload := preloadCities(ctx, cities, cache, func(city string) (WeatherData, error) {
+		return provider.Fetch(ctx, city)
+	})
+
+	// THE LOOP BELOW PLAYS THE RENDER LOOP: IT ALONE TOUCHES THE PANELS
+	issued, inFlight := 0, 0
+	requested := make(map[*cityPanel]string)
+	for issued < FETCHES || inFlight > 0 {
+		target := panels[issued%len(panels)]
+		if issued < FETCHES && !target.Pending {
+			city := cities[rand.Intn(len(cities))]
+			target.Pending = true
+			requested[target] = city
+			fetchPanel(ctx, results, target, provider.WithUnits(Celsius), city, issued%2 == 0, nil)
+			issued++
+			inFlight++
+			continue
+		}
+
+		result := <-results
+		applyResult(result, cache, time.Now())
+		inFlight--
+
+		if result.city != requested[result.panel] {
+			t.Errorf("panel got %q, but last asked for %q", result.city, requested[result.panel])
+		}
+		if result.withForecast && result.err == nil && len(result.forecast) != 1 {
+			t.Errorf("%s: forecast missing from a combined fetch", result.city)
+		}
+	}
+
+	close(done)
+	readers.Wait()
+	for !preload.finished() {
+		time.Sleep(time.Millisecond)
+	}
+
+	for i, p := range panels {
+		if p.Pending {
+			t.Errorf("panel %d still pending", i)
+		}
+		if p.Weather.Location == "Atlantis" {
+			t.Errorf("panel %d shows a failed fetch", i)
+		}
+		if p.Weather.Location != "" && p.Weather.Temperature != len(p.Weather.Location) {
+			t.Errorf("panel %d has mixed data: %+v", i, p.Weather)
+		}
+	}
+	for _, city := range cities {
+		_, ok := cache.Get(cacheKey(city, Celsius))
+		if want := city != "Atlantis"; ok != want {
+			t.Errorf("cache has %s: %v, want %v", city, ok, want)
+		}
+	}
+	if preload.failed.Load() != 1 {
+		t.Errorf("preload failed %d cities, want 1", preload.failed.Load())
+	}
+}
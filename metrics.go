@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// METRICS_SHUTDOWN_TIMEOUT bounds how long an in-flight scrape may take to
+// finish once the server is asked to stop
+const METRICS_SHUTDOWN_TIMEOUT = 5 * time.Second
+
+// cityReading is the last fetch result for one city
+type cityReading struct {
+	weather    WeatherData
+	hasReading bool
+	errors     int
+}
+
+// weatherMetrics holds the readings served on /metrics. Fetches write and
+// scrapes read from different goroutines, so every access takes the lock.
+type weatherMetrics struct {
+	mu     sync.Mutex
+	cities map[string]*cityReading
+}
+
+func newWeatherMetrics() *weatherMetrics {
+	return &weatherMetrics{cities: make(map[string]*cityReading)}
+}
+
+// Record updates the gauges for city after a fetch, or counts the error
+func (m *weatherMetrics) Record(city string, weather WeatherData, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	reading, ok := m.cities[city]
+	if !ok {
+		reading = &cityReading{}
+		m.cities[city] = reading
+	}
+	if err != nil {
+		reading.errors++
+		return
+	}
+	reading.weather = weather
+	reading.hasReading = true
+}
+
+// escapeLabel escapes a label value for the Prometheus text format
+func escapeLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+// Write writes every metric in the Prometheus text exposition format.
+// Temperatures are in Celsius whatever unit was fetched, following the
+// Prometheus convention of base units.
+func (m *weatherMetrics) Write(w io.Writer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cities := make([]string, 0, len(m.cities))
+	for city := range m.cities {
+		cities = append(cities, city)
+	}
+	slices.Sort(cities)
+
+	fmt.Fprintln(w, "# HELP go_weather_temperature_celsius Last fetched temperature.")
+	fmt.Fprintln(w, "# TYPE go_weather_temperature_celsius gauge")
+	for _, city := range cities {
+		if reading := m.cities[city]; reading.hasReading {
+			fmt.Fprintf(w, "go_weather_temperature_celsius{city=\"%s\"} %d\n", escapeLabel(city), reading.weather.Unit.ToCelsius(reading.weather.Temperature))
+		}
+	}
+
+	fmt.Fprintln(w, "# HELP go_weather_humidity_percent Last fetched relative humidity.")
+	fmt.Fprintln(w, "# TYPE go_weather_humidity_percent gauge")
+	for _, city := range cities {
+		if reading := m.cities[city]; reading.hasReading {
+			fmt.Fprintf(w, "go_weather_humidity_percent{city=\"%s\"} %d\n", escapeLabel(city), reading.weather.Humidity)
+		}
+	}
+
+	fmt.Fprintln(w, "# HELP go_weather_fetch_errors_total Failed fetches since start.")
+	fmt.Fprintln(w, "# TYPE go_weather_fetch_errors_total counter")
+	for _, city := range cities {
+		fmt.Fprintf(w, "go_weather_fetch_errors_total{city=\"%s\"} %d\n", escapeLabel(city), m.cities[city].errors)
+	}
+}
+
+func (m *weatherMetrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	m.Write(w)
+}
+
+// runMetrics fetches cities every interval and serves the readings on addr
+// at /metrics until ctx is cancelled or the process gets an interrupt, then
+// shuts the server down cleanly. Each successful fetch is also appended to
+// weatherLog.
+func runMetrics(ctx context.Context, addr string, provider Provider, cities []string, interval time.Duration, weatherLog *csvLog) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// LISTEN UP FRONT SO A BAD OR BUSY ADDRESS FAILS BEFORE ANY FETCH
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %v", addr, err)
+	}
+
+	metrics := newWeatherMetrics()
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics)
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.Serve(listener)
+	}()
+	slog.Info("Serving metrics", "addr", listener.Addr().String(), "cities", cities, "interval", interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		for _, city := range cities {
+			weather, err := provider.Fetch(ctx, city)
+			if ctx.Err() != nil {
+				break
+			}
+			metrics.Record(city, weather, err)
+			if err != nil {
+				slog.Warn("Fetch failed", "city", city, "err", err)
+				continue
+			}
+			if err := weatherLog.Append(weather, time.Now()); err != nil {
+				slog.Warn("Weather not logged", "err", err)
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case err := <-serveErr:
+			return fmt.Errorf("metrics server stopped: %v", err)
+		case <-ctx.Done():
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), METRICS_SHUTDOWN_TIMEOUT)
+			defer cancel()
+			return server.Shutdown(shutdownCtx)
+		}
+	}
+}
+
+// metricsCities is the headless city followed by any PRELOAD_CITIES not
+// already listed
+func metricsCities(city string) []string {
+	cities := []string{city}
+	for _, preload := range preloadList() {
+		if !slices.ContainsFunc(cities, func(c string) bool { return normalizeCity(c) == normalizeCity(preload) }) {
+			cities = append(cities, preload)
+		}
+	}
+	return cities
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"strings"
+	"time"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// STATUS_DURATION is how long a status message stays up
+const STATUS_DURATION = 3 * time.Second
+
+// inputBuffer holds the text box contents in a fixed rune buffer. The slot
+// after the last character is always zeroed, and the text never grows past
+// the buffer's capacity. Edits happen at the cursor, which sits between
+// characters: 0 is before the first and count after the last.
+type inputBuffer struct {
+	runes  []rune
+	count  int
+	cursor int
+}
+
+func newInputBuffer(max int) *inputBuffer {
+	return &inputBuffer{runes: make([]rune, max+1)}
+}
+
+// Max is the most characters the buffer holds
+func (b *inputBuffer) Max() int {
+	return len(b.runes) - 1
+}
+
+func (b *inputBuffer) Len() int {
+	return b.count
+}
+
+func (b *inputBuffer) Full() bool {
+	return b.count >= b.Max()
+}
+
+func (b *inputBuffer) String() string {
+	return string(b.runes[:b.count])
+}
+
+// Cursor is the number of characters before the cursor
+func (b *inputBuffer) Cursor() int {
+	return b.cursor
+}
+
+// Insert adds r at the cursor if there is room, reporting whether it did
+func (b *inputBuffer) Insert(r rune) bool {
+	if b.Full() {
+		return false
+	}
+	copy(b.runes[b.cursor+1:b.count+1], b.runes[b.cursor:b.count])
+	b.runes[b.cursor] = r
+	b.cursor++
+	b.count++
+	b.runes[b.count] = 0
+	return true
+}
+
+// Append inserts as much of runes at the cursor as fits and drops the rest
+func (b *inputBuffer) Append(runes []rune) {
+	for _, r := range runes {
+		if !b.Insert(r) {
+			return
+		}
+	}
+}
+
+// Backspace removes the character before the cursor, if any
+func (b *inputBuffer) Backspace() {
+	if b.cursor == 0 {
+		return
+	}
+	b.cursor--
+	b.Delete()
+}
+
+// Delete removes the character after the cursor, if any
+func (b *inputBuffer) Delete() {
+	if b.cursor == b.count {
+		return
+	}
+	copy(b.runes[b.cursor:b.count], b.runes[b.cursor+1:b.count+1])
+	b.count--
+	b.runes[b.count] = 0
+}
+
+// Left and Right move the cursor one character, stopping at either end
+func (b *inputBuffer) Left() {
+	b.cursor = max(b.cursor-1, 0)
+}
+
+func (b *inputBuffer) Right() {
+	b.cursor = min(b.cursor+1, b.count)
+}
+
+// Home and End jump the cursor to the start or end of the text
+func (b *inputBuffer) Home() {
+	b.cursor = 0
+}
+
+func (b *inputBuffer) End() {
+	b.cursor = b.count
+}
+
+// Set replaces the contents with text, sanitized and truncated to fit, and
+// leaves the cursor at the end
+func (b *inputBuffer) Set(text string) {
+	b.count, b.cursor = 0, 0
+	b.runes[0] = 0
+	b.Append(sanitizeText(text))
+}
+
+// statusLine is the one-line message under the input box
+type statusLine struct {
+	Message string
+	Color   rl.Color
+	ClearAt time.Time
+}
+
+// Set shows message in color for STATUS_DURATION from now
+func (s *statusLine) Set(message string, color rl.Color, now time.Time) {
+	s.Message = message
+	s.Color = color
+	s.ClearAt = now.Add(STATUS_DURATION)
+}
+
+// Clear removes the message now
+func (s *statusLine) Clear() {
+	s.Message = ""
+}
+
+// Expire clears the message once its time is up. While a fetch is pending
+// the message stays, so "Fetching..." never vanishes early.
+func (s *statusLine) Expire(now time.Time, pending bool) {
+	if s.Message != "" && !pending && now.After(s.ClearAt) {
+		s.Message = ""
+	}
+}
+
+// cooldownOver reports whether more than cooldown has passed since last
+func cooldownOver(last, now time.Time, cooldown time.Duration) bool {
+	return now.Sub(last) > cooldown
+}
+
+// cooldownRemaining is the share of cooldown still left since last, from 1
+// right after a fetch down to 0 once it is over
+func cooldownRemaining(last, now time.Time, cooldown time.Duration) float32 {
+	if cooldownOver(last, now, cooldown) {
+		return 0
+	}
+	return 1 - float32(now.Sub(last))/float32(cooldown)
+}
+
+// canSearch gates Enter and the Search button: there must be non-blank input,
+// no fetch already running for the panel, and the panel's cooldown must be over
+func canSearch(input string, panel *cityPanel, now time.Time, cooldown time.Duration) bool {
+	return strings.TrimSpace(input) != "" && !panel.Pending && cooldownOver(panel.LastFetch, now, cooldown)
+}
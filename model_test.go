@@ -0,0 +1,125 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+func TestCooldownOver(t *testing.T) {
+	last := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	cooldown := 2 * time.Second
+
+	tests := []struct {
+		since time.Duration
+		want  bool
+	}{
+		{0, false},
+		{time.Second, false},
+		{cooldown - time.Nanosecond, false},
+		{cooldown, false},
+		{cooldown + time.Nanosecond, true},
+		{time.Minute, true},
+	}
+	for _, test := range tests {
+		if got := cooldownOver(last, last.Add(test.since), cooldown); got != test.want {
+			t.Errorf("cooldownOver after %v = %v, want %v", test.since, got, test.want)
+		}
+	}
+
+	if !cooldownOver(time.Time{}, last, cooldown) {
+		t.Error("a panel that never fetched should be past its cooldown")
+	}
+}
+
+func TestCooldownRemaining(t *testing.T) {
+	last := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	cooldown := 2 * time.Second
+
+	tests := []struct {
+		since time.Duration
+		want  float32
+	}{
+		{0, 1},
+		{500 * time.Millisecond, 0.75},
+		{time.Second, 0.5},
+		{cooldown + time.Nanosecond, 0},
+		{time.Minute, 0},
+	}
+	for _, test := range tests {
+		if got := cooldownRemaining(last, last.Add(test.since), cooldown); got != test.want {
+			t.Errorf("cooldownRemaining after %v = %v, want %v", test.since, got, test.want)
+		}
+	}
+}
+
+func TestCanSearch(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	cooldown := 2 * time.Second
+
+	tests := []struct {
+		name  string
+		input string
+		panel cityPanel
+		want  bool
+	}{
+		{"ready", "Paris", cityPanel{LastFetch: now.Add(-time.Minute)}, true},
+		{"never fetched", "Paris", cityPanel{}, true},
+		{"blank input", "", cityPanel{}, false},
+		{"whitespace input", "  \t", cityPanel{}, false},
+		{"pending fetch", "Paris", cityPanel{Pending: true}, false},
+		{"pending past cooldown", "Paris", cityPanel{Pending: true, LastFetch: now.Add(-time.Minute)}, false},
+		{"inside cooldown", "Paris", cityPanel{LastFetch: now.Add(-time.Second)}, false},
+		{"at cooldown boundary", "Paris", cityPanel{LastFetch: now.Add(-cooldown)}, false},
+		{"just past cooldown", "Paris", cityPanel{LastFetch: now.Add(-cooldown - time.Millisecond)}, true},
+	}
+	for _, test := range tests {
+		if got := canSearch(test.input, &test.panel, now, cooldown); got != test.want {
+			t.Errorf("%s: canSearch = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestStatusLineExpire(t *testing.T) {
+	set := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		since   time.Duration
+		pending bool
+		want    string
+	}{
+		{"fresh", 0, false, "Fetching..."},
+		{"before ClearAt", STATUS_DURATION - time.Millisecond, false, "Fetching..."},
+		{"at ClearAt", STATUS_DURATION, false, "Fetching..."},
+		{"after ClearAt", STATUS_DURATION + time.Millisecond, false, ""},
+		{"after ClearAt while pending", STATUS_DURATION + time.Hour, true, "Fetching..."},
+	}
+	for _, test := range tests {
+		var status statusLine
+		status.Set("Fetching...", rl.White, set)
+		status.Expire(set.Add(test.since), test.pending)
+		if status.Message != test.want {
+			t.Errorf("%s: message = %q, want %q", test.name, status.Message, test.want)
+		}
+	}
+}
+
+func TestStatusLineExpiresOnceFetchLands(t *testing.T) {
+	set := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	var status statusLine
+	status.Set("Fetching...", rl.White, set)
+	status.Expire(set.Add(STATUS_DURATION*2), true)
+	status.Expire(set.Add(STATUS_DURATION*2), false)
+	if status.Message != "" {
+		t.Errorf("message = %q once the fetch finished past ClearAt, want it cleared", status.Message)
+	}
+
+	status.Set("Done", rl.White, set)
+	status.Clear()
+	if status.Message != "" {
+		t.Errorf("message = %q after Clear, want it gone", status.Message)
+	}
+}
@@ -0,0 +1,14 @@
+package main
+
+import (
+	"os"
+	"strconv"
+)
+
+// isMinimalNetwork reports whether the MINIMAL_NETWORK config flag is set.
+// In that mode only explicitly requested current-weather fetches go out;
+// preloading, forecasts and any other optional or background call is skipped.
+func isMinimalNetwork() bool {
+	enabled, _ := strconv.ParseBool(os.Getenv("MINIMAL_NETWORK"))
+	return enabled
+}
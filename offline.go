@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNoCachedData is returned offline for a city the cache has no entry for
+var ErrNoCachedData = errors.New("no cached data for this city")
+
+// ErrOffline is returned offline for anything the cache cannot answer
+var ErrOffline = errors.New("not available offline")
+
+// offlineProvider answers from the cache and never makes a request. Entries
+// are served even past their TTL, since old data beats none when offline.
+type offlineProvider struct {
+	cache Cache
+	unit  Unit
+}
+
+func (p offlineProvider) Fetch(ctx context.Context, city string) (WeatherData, error) {
+	if weather, ok := p.cache.Peek(cacheKey(city, p.unit)); ok {
+		return weather, nil
+	}
+	return WeatherData{}, ErrNoCachedData
+}
+
+// Forecast is not cached, so it is never available offline
+func (p offlineProvider) Forecast(ctx context.Context, city string) ([]ForecastEntry, error) {
+	return nil, ErrOffline
+}
+
+// Geocode returns no suggestions rather than an error, so typing stays quiet
+func (p offlineProvider) Geocode(ctx context.Context, query string) ([]GeoLocation, error) {
+	return nil, nil
+}
+
+func (p offlineProvider) WithUnits(unit Unit) Provider {
+	p.unit = unit
+	return p
+}
@@ -0,0 +1,324 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OPEN-METEO NEEDS NO KEY. BOTH URLS CAN BE OVERRIDDEN, e.g. FOR A SELF-HOSTED
+// INSTANCE, WITH OPEN_METEO_URL AND OPEN_METEO_GEO_URL.
+const (
+	OPEN_METEO_URL_DEFAULT     = "https://api.open-meteo.com/v1/forecast"
+	OPEN_METEO_GEO_URL_DEFAULT = "https://geocoding-api.open-meteo.com/v1/search"
+	OPEN_METEO_GEO_COUNT       = 10
+)
+
+// OpenMeteoClient is the keyless Provider. Open-Meteo takes coordinates only,
+// so city names are geocoded first.
+type OpenMeteoClient struct {
+	BaseURL string
+	GeoURL  string
+	// nil uses http.DefaultClient
+	HTTPClient *http.Client
+	Units      Unit
+}
+
+func newOpenMeteoClient(unit Unit) *OpenMeteoClient {
+	client := &OpenMeteoClient{
+		BaseURL:    os.Getenv("OPEN_METEO_URL"),
+		GeoURL:     os.Getenv("OPEN_METEO_GEO_URL"),
+		HTTPClient: httpClient,
+		Units:      unit,
+	}
+	if client.BaseURL == "" {
+		client.BaseURL = OPEN_METEO_URL_DEFAULT
+	}
+	if client.GeoURL == "" {
+		client.GeoURL = OPEN_METEO_GEO_URL_DEFAULT
+	}
+	return client
+}
+
+func (c *OpenMeteoClient) WithUnits(unit Unit) Provider {
+	client := *c
+	client.Units = unit
+	return &client
+}
+
+func (c *OpenMeteoClient) httpClient() *http.Client {
+	if c.HTTPClient == nil {
+		return http.DefaultClient
+	}
+	return c.HTTPClient
+}
+
+// openMeteoResponse is the subset of /v1/forecast we request. Times are Unix
+// seconds, since the request asks for timeformat=unixtime.
+type openMeteoResponse struct {
+	UTCOffsetSeconds int `json:"utc_offset_seconds"`
+	Current          struct {
+		Temperature      float64 `json:"temperature_2m"`
+		Humidity         float64 `json:"relative_humidity_2m"`
+		FeelsLike        float64 `json:"apparent_temperature"`
+		WeatherCode      int     `json:"weather_code"`
+		WindSpeed        float64 `json:"wind_speed_10m"`
+		WindDirection    *int    `json:"wind_direction_10m"`
+		PressureSeaLevel float64 `json:"pressure_msl"`
+	} `json:"current"`
+	Daily struct {
+		Time        []int64   `json:"time"`
+		WeatherCode []int     `json:"weather_code"`
+		TempMax     []float64 `json:"temperature_2m_max"`
+		TempMin     []float64 `json:"temperature_2m_min"`
+		Sunrise     []int64   `json:"sunrise"`
+		Sunset      []int64   `json:"sunset"`
+	} `json:"daily"`
+}
+
+type openMeteoGeocoding struct {
+	Results []struct {
+		Name        string  `json:"name"`
+		Admin1      string  `json:"admin1"`
+		CountryCode string  `json:"country_code"`
+		Latitude    float64 `json:"latitude"`
+		Longitude   float64 `json:"longitude"`
+	} `json:"results"`
+}
+
+func (c *OpenMeteoClient) Fetch(ctx context.Context, city string) (WeatherData, error) {
+	location, err := c.locate(ctx, city)
+	if err != nil {
+		return WeatherData{}, err
+	}
+
+	var resp openMeteoResponse
+	if err := c.get(ctx, c.BaseURL, c.forecastParams(location, 1), "fetch weather", &resp); err != nil {
+		return WeatherData{}, err
+	}
+
+	weather := resp.toWeatherData(location.Name)
+	weather.Unit = c.Units
+	weather.FetchedAt = time.Now()
+	return weather, nil
+}
+
+func (c *OpenMeteoClient) Forecast(ctx context.Context, city string) ([]ForecastEntry, error) {
+	location, err := c.locate(ctx, city)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp openMeteoResponse
+	if err := c.get(ctx, c.BaseURL, c.forecastParams(location, FORECAST_DAYS), "fetch forecast", &resp); err != nil {
+		return nil, err
+	}
+	return resp.toForecast(c.Units), nil
+}
+
+// Geocode searches by name. A ",CC" suffix, as GeoLocation.Query produces,
+// keeps only places in that country.
+func (c *OpenMeteoClient) Geocode(ctx context.Context, query string) ([]GeoLocation, error) {
+	name, country, _ := strings.Cut(query, ",")
+	name, country = strings.TrimSpace(name), strings.TrimSpace(country)
+
+	params := url.Values{}
+	params.Set("name", name)
+	params.Set("count", strconv.Itoa(OPEN_METEO_GEO_COUNT))
+
+	var resp openMeteoGeocoding
+	if err := c.get(ctx, c.GeoURL, params, "geocode", &resp); err != nil {
+		return nil, err
+	}
+
+	var locations []GeoLocation
+	for _, result := range resp.Results {
+		if country != "" && !strings.EqualFold(result.CountryCode, country) {
+			continue
+		}
+		locations = append(locations, GeoLocation{
+			Name:    result.Name,
+			State:   result.Admin1,
+			Country: result.CountryCode,
+			Lat:     result.Latitude,
+			Lon:     result.Longitude,
+		})
+		if len(locations) == GEOCODE_LIMIT {
+			break
+		}
+	}
+	return locations, nil
+}
+
+// locate turns the input into coordinates: "lat,lon" is used as is and
+// anything else is geocoded, taking the best match
+func (c *OpenMeteoClient) locate(ctx context.Context, input string) (GeoLocation, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return GeoLocation{}, ErrEmptyQuery
+	}
+	if isCoords(input) {
+		lat, lon, err := parseCoords(input)
+		if err != nil {
+			return GeoLocation{}, err
+		}
+		return GeoLocation{Name: formatCoords(lat, lon), Lat: lat, Lon: lon}, nil
+	}
+	if _, ok := parseCityID(input); ok {
+		return GeoLocation{}, fmt.Errorf("city IDs only work with the %s provider", PROVIDER_OPENWEATHERMAP)
+	}
+
+	locations, err := c.Geocode(ctx, input)
+	if err != nil {
+		return GeoLocation{}, err
+	}
+	if len(locations) == 0 {
+		return GeoLocation{}, ErrCityNotFound
+	}
+	return locations[0], nil
+}
+
+func (c *OpenMeteoClient) forecastParams(location GeoLocation, days int) url.Values {
+	params := url.Values{}
+	params.Set("latitude", strconv.FormatFloat(location.Lat, 'f', -1, 64))
+	params.Set("longitude", strconv.FormatFloat(location.Lon, 'f', -1, 64))
+	params.Set("current", "temperature_2m,relative_humidity_2m,apparent_temperature,weather_code,wind_speed_10m,wind_direction_10m,pressure_msl")
+	params.Set("daily", "weather_code,temperature_2m_max,temperature_2m_min,sunrise,sunset")
+	params.Set("forecast_days", strconv.Itoa(days))
+	params.Set("timezone", "auto")
+	params.Set("timeformat", "unixtime")
+
+	// WIND MATCHES OPENWEATHER: m/s FOR METRIC, mph FOR IMPERIAL
+	if c.Units == Fahrenheit {
+		params.Set("temperature_unit", "fahrenheit")
+		params.Set("wind_speed_unit", "mph")
+	} else {
+		params.Set("wind_speed_unit", "ms")
+	}
+	return params
+}
+
+// get requests endpoint with params and decodes the JSON body into v
+func (c *OpenMeteoClient) get(ctx context.Context, endpoint string, params url.Values, action string, v any) error {
+	requestURL, err := withQuery(endpoint, params)
+	if err != nil {
+		return err
+	}
+
+	resp, err := retryGet(ctx, c.httpClient(), requestURL)
+	if err != nil {
+		return requestError(action, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return requestError("read response", err)
+	}
+	recordRawResponse(body)
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return rateLimitError(resp, time.Now())
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("API error (%d): %s", resp.StatusCode, string(body))
+	}
+	return decodeResponse(body, v)
+}
+
+// toWeatherData maps the response onto WeatherData the way the OpenWeather
+// response is mapped, so the UI cannot tell the providers apart
+func (r openMeteoResponse) toWeatherData(location string) WeatherData {
+	condition := wmoCondition(r.Current.WeatherCode)
+	weather := WeatherData{
+		Location:       location,
+		Temperature:    int(r.Current.Temperature),
+		FeelsLike:      int(r.Current.FeelsLike),
+		Humidity:       int(r.Current.Humidity),
+		WindSpeed:      float32(r.Current.WindSpeed),
+		Pressure:       int(r.Current.PressureSeaLevel),
+		Visibility:     VISIBILITY_UNKNOWN,
+		WindDeg:        WIND_DEG_UNKNOWN,
+		TimezoneOffset: r.UTCOffsetSeconds,
+		Condition:      condition.Main,
+		ConditionID:    condition.ID,
+		Description:    condition.Description,
+	}
+
+	if r.Current.WindDirection != nil {
+		weather.WindDeg = (*r.Current.WindDirection%360 + 360) % 360
+	}
+
+	daily := r.Daily
+	if len(daily.TempMin) > 0 && len(daily.TempMax) > 0 {
+		weather.TempMin, weather.TempMax = int(daily.TempMin[0]), int(daily.TempMax[0])
+		weather.HasTempRange = true
+	}
+	if len(daily.Sunrise) > 0 && len(daily.Sunset) > 0 {
+		weather.Sunrise = unixLocalTime(daily.Sunrise[0], weather.TimezoneOffset)
+		weather.Sunset = unixLocalTime(daily.Sunset[0], weather.TimezoneOffset)
+	}
+
+	return weather
+}
+
+// toForecast gives one entry per day with the day's high as its temperature
+func (r openMeteoResponse) toForecast(unit Unit) []ForecastEntry {
+	daily := r.Daily
+	days := min(len(daily.Time), len(daily.TempMax), len(daily.WeatherCode), FORECAST_DAYS)
+
+	forecast := make([]ForecastEntry, 0, days)
+	for i := 0; i < days; i++ {
+		forecast = append(forecast, ForecastEntry{
+			Time:        time.Unix(daily.Time[i], 0),
+			Temperature: int(daily.TempMax[i]),
+			Condition:   wmoCondition(daily.WeatherCode[i]).Main,
+			Unit:        unit,
+		})
+	}
+	return forecast
+}
+
+// wmoConditions maps WMO weather codes, which Open-Meteo reports, to the
+// nearest OpenWeather condition, so icons, effects and severity all work
+var wmoConditions = map[int]OpenWeatherCondition{
+	0:  {800, "Clear", "clear sky"},
+	1:  {801, "Clouds", "mainly clear"},
+	2:  {802, "Clouds", "partly cloudy"},
+	3:  {804, "Clouds", "overcast"},
+	45: {741, "Fog", "fog"},
+	48: {741, "Fog", "depositing rime fog"},
+	51: {300, "Drizzle", "light drizzle"},
+	53: {301, "Drizzle", "moderate drizzle"},
+	55: {302, "Drizzle", "dense drizzle"},
+	56: {301, "Drizzle", "light freezing drizzle"},
+	57: {302, "Drizzle", "dense freezing drizzle"},
+	61: {500, "Rain", "slight rain"},
+	63: {501, "Rain", "moderate rain"},
+	65: {502, "Rain", "heavy rain"},
+	66: {511, "Rain", "light freezing rain"},
+	67: {511, "Rain", "heavy freezing rain"},
+	71: {600, "Snow", "slight snow fall"},
+	73: {601, "Snow", "moderate snow fall"},
+	75: {602, "Snow", "heavy snow fall"},
+	77: {600, "Snow", "snow grains"},
+	80: {520, "Rain", "slight rain showers"},
+	81: {521, "Rain", "moderate rain showers"},
+	82: {522, "Rain", "violent rain showers"},
+	85: {620, "Snow", "slight snow showers"},
+	86: {622, "Snow", "heavy snow showers"},
+	95: {211, "Thunderstorm", "thunderstorm"},
+	96: {201, "Thunderstorm", "thunderstorm with slight hail"},
+	99: {202, "Thunderstorm", "thunderstorm with heavy hail"},
+}
+
+// wmoCondition looks up code, leaving unknown codes without a condition
+func wmoCondition(code int) OpenWeatherCondition {
+	return wmoConditions[code]
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+const openMeteoFixture = `{
+	"utc_offset_seconds": 3600,
+	"current": {
+		"temperature_2m": 4.6, "relative_humidity_2m": 81, "apparent_temperature": 1.2,
+		"weather_code": 61, "wind_speed_10m": 5.4, "wind_direction_10m": 370, "pressure_msl": 1008.7
+	},
+	"daily": {
+		"time": [1700000000, 1700086400, 1700172800],
+		"weather_code": [61, 3, 0],
+		"temperature_2m_max": [6.9, 8.2, 10.5],
+		"temperature_2m_min": [-1.4, 2, 3],
+		"sunrise": [1700030000, 1700116400, 1700202800],
+		"sunset": [1700060000, 1700146400, 1700232800]
+	}
+}`
+
+func decodeOpenMeteo(t *testing.T, body string) openMeteoResponse {
+	t.Helper()
+
+	var resp openMeteoResponse
+	if err := json.Unmarshal([]byte(body), &resp); err != nil {
+		t.Fatalf("fixture does not decode: %v", err)
+	}
+	return resp
+}
+
+func TestOpenMeteoToWeatherData(t *testing.T) {
+	w := decodeOpenMeteo(t, openMeteoFixture).toWeatherData("Oslo")
+
+	if w.Location != "Oslo" || w.TimezoneOffset != 3600 {
+		t.Errorf("Location, TimezoneOffset = %q, %d; want Oslo, 3600", w.Location, w.TimezoneOffset)
+	}
+	if w.Temperature != 4 || w.FeelsLike != 1 || w.Humidity != 81 || w.Pressure != 1008 {
+		t.Errorf("current reading = %+v", w)
+	}
+	if w.WindSpeed != 5.4 || w.WindDeg != 10 {
+		t.Errorf("wind = %v from %d, want 5.4 from 10", w.WindSpeed, w.WindDeg)
+	}
+	if w.Condition != "Rain" || w.ConditionID != 500 || w.Description != "slight rain" {
+		t.Errorf("condition = %q %d %q, want WMO 61 as Rain 500", w.Condition, w.ConditionID, w.Description)
+	}
+	if !w.HasTempRange || w.TempMin != -1 || w.TempMax != 6 {
+		t.Errorf("range = %d..%d (%v), want -1..6", w.TempMin, w.TempMax, w.HasTempRange)
+	}
+	if w.Visibility != VISIBILITY_UNKNOWN {
+		t.Errorf("Visibility = %d, want VISIBILITY_UNKNOWN", w.Visibility)
+	}
+	if want := unixLocalTime(1700030000, 3600); w.Sunrise != want {
+		t.Errorf("Sunrise = %v, want %v", w.Sunrise, want)
+	}
+}
+
+func TestOpenMeteoToWeatherDataSparse(t *testing.T) {
+	w := decodeOpenMeteo(t, `{"current": {"temperature_2m": 12, "weather_code": 42}}`).toWeatherData("Lima")
+
+	if w.WindDeg != WIND_DEG_UNKNOWN || w.HasTempRange {
+		t.Errorf("WindDeg, HasTempRange = %d, %v; want WIND_DEG_UNKNOWN, false", w.WindDeg, w.HasTempRange)
+	}
+	if w.Condition != "" || w.ConditionID != 0 {
+		t.Errorf("unknown WMO code gave %q %d, want no condition", w.Condition, w.ConditionID)
+	}
+	if !w.Sunrise.IsZero() || !w.Sunset.IsZero() {
+		t.Errorf("Sunrise, Sunset = %v, %v; want zero without daily data", w.Sunrise, w.Sunset)
+	}
+}
+
+func TestOpenMeteoToForecast(t *testing.T) {
+	forecast := decodeOpenMeteo(t, openMeteoFixture).toForecast(Celsius)
+
+	want := []ForecastEntry{
+		{Time: time.Unix(1700000000, 0), Temperature: 6, Condition: "Rain", Unit: Celsius},
+		{Time: time.Unix(1700086400, 0), Temperature: 8, Condition: "Clouds", Unit: Celsius},
+		{Time: time.Unix(1700172800, 0), Temperature: 10, Condition: "Clear", Unit: Celsius},
+	}
+	if len(forecast) != len(want) {
+		t.Fatalf("got %d entries, want %d", len(forecast), len(want))
+	}
+	for i := range want {
+		if !forecast[i].Time.Equal(want[i].Time) || forecast[i].Temperature != want[i].Temperature ||
+			forecast[i].Condition != want[i].Condition || forecast[i].Unit != want[i].Unit {
+			t.Errorf("entry %d = %+v, want %+v", i, forecast[i], want[i])
+		}
+	}
+
+	// RAGGED ARRAYS STOP AT THE SHORTEST
+	ragged := decodeOpenMeteo(t, `{"daily": {"time": [1, 2, 3], "weather_code": [0, 0], "temperature_2m_max": [1, 2, 3]}}`)
+	if got := len(ragged.toForecast(Celsius)); got != 2 {
+		t.Errorf("ragged daily gave %d entries, want 2", got)
+	}
+}
+
+// serveOpenMeteo starts forecast and geocoding servers and records the
+// forecast query
+func serveOpenMeteo(t *testing.T, got *url.Values) *OpenMeteoClient {
+	t.Helper()
+
+	geo := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("name") != "Bergen" {
+			w.Write([]byte(`{}`))
+			return
+		}
+		w.Write([]byte(`{"results": [
+			{"name": "Bergen", "admin1": "Lower Saxony", "country_code": "DE", "latitude": 52.8, "longitude": 9.96},
+			{"name": "Bergen", "admin1": "Vestland", "country_code": "NO", "latitude": 60.39, "longitude": 5.32}
+		]}`))
+	}))
+	t.Cleanup(geo.Close)
+
+	forecast := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*got = r.URL.Query()
+		w.Write([]byte(openMeteoFixture))
+	}))
+	t.Cleanup(forecast.Close)
+
+	return &OpenMeteoClient{BaseURL: forecast.URL, GeoURL: geo.URL, HTTPClient: forecast.Client(), Units: Fahrenheit}
+}
+
+func TestOpenMeteoClientFetch(t *testing.T) {
+	var got url.Values
+	client := serveOpenMeteo(t, &got)
+
+	weather, err := client.Fetch(context.Background(), "Bergen,NO")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"latitude": "60.39", "longitude": "5.32", "forecast_days": "1",
+		"temperature_unit": "fahrenheit", "wind_speed_unit": "mph", "timeformat": "unixtime",
+	}
+	for key, value := range want {
+		if got.Get(key) != value {
+			t.Errorf("%s = %q, want %q", key, got.Get(key), value)
+		}
+	}
+	if weather.Location != "Bergen" || weather.Unit != Fahrenheit || weather.FetchedAt.IsZero() {
+		t.Errorf("weather = %+v, want Bergen in %v with FetchedAt set", weather, Fahrenheit)
+	}
+}
+
+func TestOpenMeteoClientForecast(t *testing.T) {
+	var got url.Values
+	client := serveOpenMeteo(t, &got)
+
+	forecast, err := client.Forecast(context.Background(), "60.39,5.32")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Get("latitude") != "60.39" || got.Get("forecast_days") != "5" {
+		t.Errorf("query = %v, want the coordinates as given and 5 days", got)
+	}
+	if len(forecast) != 3 || forecast[0].Unit != Fahrenheit {
+		t.Errorf("forecast = %+v, want 3 entries in %v", forecast, Fahrenheit)
+	}
+}
+
+func TestOpenMeteoClientLocateErrors(t *testing.T) {
+	var got url.Values
+	client := serveOpenMeteo(t, &got)
+
+	tests := []struct {
+		input string
+		want  error
+	}{
+		{"  ", ErrEmptyQuery},
+		{"Atlantis", ErrCityNotFound},
+		{"Bergen,SE", ErrCityNotFound},
+	}
+	for _, test := range tests {
+		if _, err := client.Fetch(context.Background(), test.input); err != test.want {
+			t.Errorf("Fetch(%q) err = %v, want %v", test.input, err, test.want)
+		}
+	}
+	if _, err := client.Fetch(context.Background(), "id:2643743"); err == nil {
+		t.Error("Fetch(id:2643743) succeeded, want the city ID error")
+	}
+	if got != nil {
+		t.Errorf("forecast endpoint was called with %v, want no call", got)
+	}
+}
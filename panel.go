@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// drawWeatherPanel draws the current conditions inside panel. The condition is
+// shown as an icon when one is loaded, otherwise as text.
+func drawWeatherPanel(font rl.Font, theme Theme, icons iconSet, weather WeatherData, hidden map[string]bool, panel rl.Rectangle) {
+	layout := computePanelLayout(panel)
+
+	rl.DrawRectangleRec(panel, theme.Box)
+	rl.DrawRectangleLinesEx(panel, 2, theme.Text)
+	drawSeverityAccent(font, theme, conditionSeverity(weather), panel)
+
+	rl.DrawTextEx(
+		font,
+		weather.Location,
+		layout.Location, 32, 0, theme.Accent,
+	)
+
+	rl.DrawTextEx(
+		font,
+		formatTemperature(weather.Temperature, weather.Unit),
+		layout.Temperature, 48, 0, tempColor(weather.Unit.ToCelsius(weather.Temperature)),
+	)
+
+	// THE LONGER DESCRIPTION GOES UNDER THE CONDITION TEXT, OR BESIDE THE
+	// ICON, AND STOPS SHORT OF THE INFO GRID
+	subtitle := layout.Description
+	if icon, ok := icons.Icon(weather.Condition); ok {
+		drawIcon(icon, layout.ConditionIcon)
+		subtitle = layout.IconCaption
+	} else {
+		rl.DrawTextEx(
+			font,
+			conditionText(weather),
+			layout.Condition, 24, 0, theme.Text,
+		)
+	}
+	if description := conditionSubtitle(weather); description != "" {
+		rl.DrawTextEx(
+			font,
+			truncateText(font, description, 14, layout.InfoGrid.X-subtitle.X-10),
+			subtitle, 14, 0, theme.Muted,
+		)
+	}
+
+	// SHOWN EVEN WHEN BOTH EQUAL THE CURRENT TEMPERATURE
+	if weather.HasTempRange {
+		rl.DrawTextEx(
+			font,
+			fmt.Sprintf("H: %s  L: %s", formatTemperature(weather.TempMax, weather.Unit), formatTemperature(weather.TempMin, weather.Unit)),
+			layout.TempRange, 18, 0, theme.Text,
+		)
+	}
+
+	// A LARGE WIND CHILL OR HEAT INDEX GETS A WARNING MARK AND COLOR
+	if !hidden[FEELS_LIKE_FIELD] {
+		position, color := layout.FeelsLike, theme.Muted
+		if feelsLikeDiverges(weather) {
+			drawWarningMark(rl.NewVector2(position.X, position.Y+2), 16, theme.Warn, theme.Box)
+			position.X += 22
+			color = theme.Warn
+		}
+		rl.DrawTextEx(
+			font,
+			fmt.Sprintf("Feels like: %s", formatTemperature(weather.FeelsLike, weather.Unit)),
+			position, 18, 0, color,
+		)
+	}
+
+	rl.DrawTextEx(
+		font,
+		fmt.Sprintf("Local time: %s", formatTime(cityLocalTime(time.Now(), weather.TimezoneOffset))),
+		layout.LocalTime, 18, 0, theme.Muted,
+	)
+
+	if !weather.Sunrise.IsZero() && !weather.Sunset.IsZero() {
+		rl.DrawTextEx(
+			font,
+			fmt.Sprintf("Sunrise: %s  Sunset: %s", formatTime(weather.Sunrise), formatTime(weather.Sunset)),
+			layout.SunTimes, 18, 0, theme.Muted,
+		)
+	}
+
+	drawInfoGrid(font, theme, weather, hidden, layout.InfoGrid)
+}
+
+// drawStaleOverlay washes out the panel and says how old the data is
+func drawStaleOverlay(font rl.Font, theme Theme, age time.Duration, panel rl.Rectangle) {
+	rl.DrawRectangleRec(panel, rl.Fade(theme.Background, 0.6))
+
+	rl.DrawTextEx(
+		font,
+		fmt.Sprintf("Stale: data is %v old", age.Truncate(time.Minute)),
+		rl.NewVector2(panel.X+20, panel.Y+panel.Height-30), 18, 0, theme.Warn,
+	)
+}
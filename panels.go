@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// MAX_PANELS CITIES CAN BE WATCHED SIDE BY SIDE
+const (
+	MAX_PANELS int     = 3
+	PANEL_GAP  float32 = 10
+)
+
+// cityPanel is one city on screen. Each panel fetches and cools down on its
+// own, so one slow city never holds up another.
+type cityPanel struct {
+	// Query is what Weather was fetched with. Refreshes and the cache use it
+	// rather than Weather.Location, the API's name for the place, which
+	// would lose coordinates, city IDs and a picked country.
+	Query     string
+	Weather   WeatherData
+	LastFetch time.Time
+	Pending   bool
+}
+
+// panelResult routes a finished fetch back to the panel that asked for it
+type panelResult struct {
+	panel *cityPanel
+	cityResult
+}
+
+// fetchPanel fetches city for target on its own goroutine and leaves the
+// result on results for the render loop. It only touches its arguments, so
+// the concurrent path runs the same with or without the GUI. A successful
+// fetch is appended to weatherLog, which may be nil.
+func fetchPanel(ctx context.Context, results chan<- panelResult, target *cityPanel, provider Provider, city string, withForecast bool, weatherLog *csvLog) {
+	go func() {
+		result := fetchCity(ctx, provider, city, withForecast)
+		if result.err == nil {
+			if err := weatherLog.Append(result.weather, time.Now()); err != nil {
+				slog.Warn("Weather not logged", "err", err)
+			}
+		}
+
+		// NOBODY READS results ONCE THE LOOP HAS ENDED
+		select {
+		case results <- panelResult{target, result}:
+		case <-ctx.Done():
+		}
+	}()
+}
+
+// applyResult stores a finished fetch on the panel it is for, and in the
+// cache when it succeeded. Only the render loop calls it, so the panels are
+// never written from two goroutines.
+func applyResult(result panelResult, cache Cache, now time.Time) {
+	result.panel.Pending = false
+	if result.err != nil {
+		return
+	}
+
+	result.weather.PressureTrend = pressureTrend(result.panel.Weather, result.weather)
+	result.panel.Query = result.city
+	result.panel.Weather = result.weather
+	result.panel.LastFetch = now
+	cache.Set(cacheKey(result.city, result.weather.Unit), result.weather, cacheTTL)
+}
+
+// removePanel drops panels[index] and returns the index to make active next
+func removePanel(panels []*cityPanel, index int) ([]*cityPanel, int) {
+	panels = append(panels[:index], panels[index+1:]...)
+	return panels, min(index, len(panels)-1)
+}
+
+// panelGrid splits area into count equal columns
+func panelGrid(area rl.Rectangle, count int) []rl.Rectangle {
+	width := (area.Width - PANEL_GAP*float32(count-1)) / float32(count)
+
+	cells := make([]rl.Rectangle, count)
+	for i := range cells {
+		cells[i] = rl.NewRectangle(area.X+float32(i)*(width+PANEL_GAP), area.Y, width, area.Height)
+	}
+	return cells
+}
+
+// clickedPanel returns the index of the grid cell clicked this frame, or -1
+func clickedPanel(cells []rl.Rectangle) int {
+	for i, cell := range cells {
+		if buttonClicked(cell) {
+			return i
+		}
+	}
+	return -1
+}
+
+// drawPanelCard draws a compact panel for the grid. The active panel, which
+// the input box searches for, gets an accent border.
+func drawPanelCard(font rl.Font, theme Theme, panel *cityPanel, labels map[string]string, cell rl.Rectangle, active bool) {
+	rl.DrawRectangleRec(cell, theme.Box)
+
+	border := theme.Muted
+	if active {
+		border = theme.Accent
+	}
+	rl.DrawRectangleLinesEx(cell, 2, border)
+
+	weather := withLabel(panel.Weather, labels)
+	if weather.Location == "" {
+		label := "No data"
+		if panel.Pending {
+			label = "Fetching..."
+		}
+		rl.DrawTextEx(font, label, rl.NewVector2(cell.X+15, cell.Y+15), 18, 0, theme.Muted)
+		return
+	}
+
+	width := cell.Width - 30
+	rl.DrawTextEx(font, truncateText(font, weather.Location, 24, width), rl.NewVector2(cell.X+15, cell.Y+15), 24, 0, theme.Accent)
+	rl.DrawTextEx(font, formatTemperature(weather.Temperature, weather.Unit), rl.NewVector2(cell.X+15, cell.Y+45), 40, 0, tempColor(weather.Unit.ToCelsius(weather.Temperature)))
+	rl.DrawTextEx(font, truncateText(font, conditionText(weather), 18, width), rl.NewVector2(cell.X+15, cell.Y+95), 18, 0, theme.Text)
+	rl.DrawTextEx(font, fmt.Sprintf("Humidity: %d%%", weather.Humidity), rl.NewVector2(cell.X+15, cell.Y+125), 16, 0, theme.Muted)
+	rl.DrawTextEx(font, truncateText(font, fmt.Sprintf("Wind: %s", formatWind(weather.WindSpeed, weather.Unit)), 16, width), rl.NewVector2(cell.X+15, cell.Y+147), 16, 0, theme.Muted)
+}
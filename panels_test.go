@@ -0,0 +1,47 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestApplyResultKeepsQuery checks that a panel remembers what it was
+// searched by, not the name the API answered with, so a refresh asks for
+// the same place and hits the same cache entry
+func TestApplyResultKeepsQuery(t *testing.T) {
+	tests := []struct {
+		name, query, location string
+	}{
+		{"picked place", formatCoords(39.8, -89.64), "Springfield"},
+		{"picked country", "Springfield,AU", "Springfield"},
+		{"coordinates", "51.5074,-0.1278", "London"},
+		{"coordinates without a place", "-45,-130", "-45,-130"},
+		{"city ID", "id:2643743", "London"},
+	}
+	for _, test := range tests {
+		cache := newMemoryCache()
+		panel := &cityPanel{Pending: true}
+		weather := WeatherData{Location: test.location, Unit: Celsius}
+
+		applyResult(panelResult{panel, cityResult{city: test.query, weather: weather}}, cache, time.Now())
+
+		if panel.Query != test.query || panel.Weather.Location != test.location {
+			t.Errorf("%s: panel has query %q showing %q, want %q showing %q", test.name, panel.Query, panel.Weather.Location, test.query, test.location)
+		}
+		if _, ok := cache.Get(cacheKey(test.query, Celsius)); !ok {
+			t.Errorf("%s: cache has no entry for %q", test.name, test.query)
+		}
+	}
+}
+
+func TestApplyResultFailureKeepsQuery(t *testing.T) {
+	cache := newMemoryCache()
+	panel := &cityPanel{Query: "Springfield,AU", Weather: WeatherData{Location: "Springfield"}, Pending: true}
+
+	applyResult(panelResult{panel, cityResult{city: "Atlantis", err: errors.New("city not found")}}, cache, time.Now())
+
+	if panel.Pending || panel.Query != "Springfield,AU" || panel.Weather.Location != "Springfield" {
+		t.Errorf("panel = %+v, want the shown city kept and no longer pending", panel)
+	}
+}
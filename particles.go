@@ -0,0 +1,83 @@
+package main
+
+import (
+	"math/rand"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// PARTICLE_MAX BOUNDS THE OVERLAY SO IT STAYS CHEAP AT 60 FPS.
+// SPEEDS ARE IN PIXELS PER SECOND.
+const (
+	PARTICLE_MAX       = 150
+	PARTICLE_SPAWN     = 4
+	RAIN_SPEED         = 420
+	SNOW_SPEED         = 60
+	SNOW_DRIFT         = 25
+	RAIN_STREAK_LENGTH = 10
+)
+
+// particle is one falling drop or flake
+type particle struct {
+	Position rl.Vector2
+	Velocity rl.Vector2
+}
+
+// particleSystem drives the rain and snow overlay. It is empty for any other
+// condition.
+type particleSystem struct {
+	condition string
+	particles []particle
+}
+
+// hasParticles reports whether condition gets an overlay
+func hasParticles(condition string) bool {
+	return condition == "Rain" || condition == "Snow"
+}
+
+func (s *particleSystem) spawn(width float32) particle {
+	p := particle{Position: rl.NewVector2(rand.Float32()*width, -RAIN_STREAK_LENGTH)}
+	if s.condition == "Snow" {
+		p.Velocity = rl.NewVector2((rand.Float32()*2-1)*SNOW_DRIFT, SNOW_SPEED*(0.6+rand.Float32()*0.8))
+	} else {
+		p.Velocity = rl.NewVector2(0, RAIN_SPEED*(0.8+rand.Float32()*0.4))
+	}
+	return p
+}
+
+// Update moves the particles by dt seconds, recycling any that leave the
+// window. A condition change clears the overlay and starts over.
+func (s *particleSystem) Update(condition string, width, height, dt float32) {
+	if condition != s.condition {
+		s.condition = condition
+		s.particles = s.particles[:0]
+	}
+	if !hasParticles(condition) {
+		return
+	}
+
+	for i := 0; i < PARTICLE_SPAWN && len(s.particles) < PARTICLE_MAX; i++ {
+		p := s.spawn(width)
+		p.Position.Y = rand.Float32() * -height / 4
+		s.particles = append(s.particles, p)
+	}
+
+	for i := range s.particles {
+		p := &s.particles[i]
+		p.Position = rl.Vector2Add(p.Position, rl.Vector2Scale(p.Velocity, dt))
+		if p.Position.Y > height || p.Position.X < 0 || p.Position.X > width {
+			*p = s.spawn(width)
+		}
+	}
+}
+
+// Draw draws rain as short streaks and snow as small flakes
+func (s *particleSystem) Draw(color rl.Color) {
+	for _, p := range s.particles {
+		if s.condition == "Snow" {
+			rl.DrawCircleV(p.Position, 2, color)
+		} else {
+			rl.DrawLineV(p.Position, rl.NewVector2(p.Position.X, p.Position.Y+RAIN_STREAK_LENGTH), color)
+		}
+	}
+}
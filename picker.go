@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"strings"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// cityPicker offers a choice of places when a searched name matches more than
+// one, e.g. "Springfield". The name is geocoded alongside the weather fetch,
+// so a search with a single match waits on nothing extra.
+type cityPicker struct {
+	cache   *geocodeCache
+	results chan suggestResult
+	cancel  context.CancelFunc
+	query   string
+
+	Candidates []GeoLocation
+}
+
+func newCityPicker(cache *geocodeCache) *cityPicker {
+	return &cityPicker{cache: cache, results: make(chan suggestResult, 1)}
+}
+
+// mayBeAmbiguous reports whether query is a bare name that could match
+// several places. Coordinates, "id:" and "City,Country" queries are already
+// exact.
+func mayBeAmbiguous(query string) bool {
+	if _, ok := parseCityID(query); ok {
+		return false
+	}
+	return query != "" && !isCoords(query) && !strings.Contains(query, ",")
+}
+
+// pickCandidates returns the places to choose from: none for zero or one
+// match, since then there is nothing to ask
+func pickCandidates(locations []GeoLocation) []GeoLocation {
+	if len(locations) < 2 {
+		return nil
+	}
+	return locations
+}
+
+// Check starts a lookup for a submitted query, dropping any earlier choice
+func (p *cityPicker) Check(ctx context.Context, query string) {
+	p.Clear()
+	if !mayBeAmbiguous(query) {
+		return
+	}
+	p.query = query
+
+	lookupCtx, cancel := context.WithCancel(ctx)
+	p.cancel = cancel
+	go func() {
+		locations, err := p.cache.Lookup(lookupCtx, query)
+		if err != nil {
+			return
+		}
+		select {
+		case p.results <- suggestResult{query: query, locations: locations}:
+		case <-lookupCtx.Done():
+		}
+	}()
+}
+
+// Update collects a finished lookup without blocking. Editing the input away
+// from the searched text drops the choice.
+func (p *cityPicker) Update(input string) {
+	if p.query != "" && strings.TrimSpace(input) != p.query {
+		p.Clear()
+	}
+
+	select {
+	case result := <-p.results:
+		if result.query == p.query {
+			p.Candidates = pickCandidates(result.locations)
+		}
+	default:
+	}
+}
+
+func (p *cityPicker) Clear() {
+	if p.cancel != nil {
+		p.cancel()
+		p.cancel = nil
+	}
+	p.query = ""
+	p.Candidates = nil
+}
+
+// Clicked returns the index of the candidate clicked this frame, or -1. The
+// rows sit under a header row below the below rectangle.
+func (p *cityPicker) Clicked(below rl.Rectangle) int {
+	return clickedSuggestion(p.Candidates, suggestionRow(below, 0))
+}
+
+// drawCityPicker draws the candidates as a dropdown under below, headed by a
+// line saying why it is there
+func drawCityPicker(font rl.Font, theme Theme, candidates []GeoLocation, below rl.Rectangle) {
+	if len(candidates) == 0 {
+		return
+	}
+
+	header := suggestionRow(below, 0)
+	rl.DrawRectangleRec(header, theme.Box)
+	rl.DrawRectangleLinesEx(header, 1, theme.Muted)
+	rl.DrawTextEx(font, "Several places match - pick one", rl.NewVector2(header.X+5, header.Y+4), 16, 0, theme.Muted)
+
+	drawSuggestions(font, theme, candidates, header)
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMayBeAmbiguous(t *testing.T) {
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{"", false},
+		{"Springfield", true},
+		{"New York", true},
+		{"Springfield,US", false},
+		{"Paris, FR", false},
+		{"51.5,-0.13", false},
+		{"91,0", false},
+		{"id:2643743", false},
+		{"ID:2643743", false},
+		{"id:abc", true},
+	}
+	for _, test := range tests {
+		if got := mayBeAmbiguous(test.query); got != test.want {
+			t.Errorf("mayBeAmbiguous(%q) = %v, want %v", test.query, got, test.want)
+		}
+	}
+}
+
+func TestPickCandidates(t *testing.T) {
+	springfields := []GeoLocation{
+		{Name: "Springfield", State: "Illinois", Country: "US"},
+		{Name: "Springfield", State: "Missouri", Country: "US"},
+		{Name: "Springfield", Country: "AU"},
+	}
+	tests := []struct {
+		name      string
+		locations []GeoLocation
+		want      int
+	}{
+		{"none", nil, 0},
+		{"one", springfields[:1], 0},
+		{"two", springfields[:2], 2},
+		{"many", springfields, 3},
+	}
+	for _, test := range tests {
+		if got := pickCandidates(test.locations); len(got) != test.want {
+			t.Errorf("%s: got %d candidates, want %d", test.name, len(got), test.want)
+		}
+	}
+}
+
+func TestCityPickerCheck(t *testing.T) {
+	picker := newCityPicker(newGeocodeCache(func(ctx context.Context, query string) ([]GeoLocation, error) {
+		return []GeoLocation{{Name: query, Country: "US"}, {Name: query, Country: "AU"}}, nil
+	}))
+	t.Cleanup(picker.Clear)
+
+	picker.Check(context.Background(), "Springfield")
+	deadline := time.Now().Add(time.Second)
+	for len(picker.Candidates) == 0 && time.Now().Before(deadline) {
+		picker.Update("Springfield")
+		time.Sleep(time.Millisecond)
+	}
+	if len(picker.Candidates) != 2 {
+		t.Fatalf("got %d candidates, want 2", len(picker.Candidates))
+	}
+
+	// EDITING THE INPUT DROPS THE CHOICE
+	picker.Update("Springfiel")
+	if picker.Candidates != nil {
+		t.Errorf("candidates = %v after editing the input, want none", picker.Candidates)
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	PRELOAD_CONCURRENCY int = 3
+	PRELOAD_INTERVAL        = 250 * time.Millisecond
+)
+
+// preloadProgress is written by the preload goroutines and read by the UI
+type preloadProgress struct {
+	total  int
+	done   atomic.Int32
+	failed atomic.Int32
+}
+
+func (p *preloadProgress) finished() bool {
+	return int(p.done.Load()) >= p.total
+}
+
+// summary describes the finished batch, e.g. "4 of 5 updated, 1 failed"
+func (p *preloadProgress) summary() string {
+	failed := int(p.failed.Load())
+	if failed == 0 {
+		return fmt.Sprintf("%d of %d updated", p.total, p.total)
+	}
+	return fmt.Sprintf("%d of %d updated, %d failed", p.total-failed, p.total, failed)
+}
+
+// preloadList reads the comma separated PRELOAD_CITIES config value
+func preloadList() []string {
+	var cities []string
+	for _, city := range strings.Split(os.Getenv("PRELOAD_CITIES"), ",") {
+		if city = strings.TrimSpace(city); city != "" {
+			cities = append(cities, city)
+		}
+	}
+	return cities
+}
+
+// preloadCities fetches every city into the cache in the background. Starts are
+// spaced by PRELOAD_INTERVAL and at most PRELOAD_CONCURRENCY requests run at
+// once. A failed city is logged and counted without stopping the others.
+// fetch is passed in so the concurrent path can be exercised without the API.
+// Cancelling ctx stops any cities not yet started.
+func preloadCities(ctx context.Context, cities []string, cache Cache, fetch func(city string) (WeatherData, error)) *preloadProgress {
+	progress := &preloadProgress{total: len(cities)}
+
+	go func() {
+		var wg sync.WaitGroup
+		slots := make(chan struct{}, PRELOAD_CONCURRENCY)
+		limiter := time.NewTicker(PRELOAD_INTERVAL)
+		defer limiter.Stop()
+
+		for i, city := range cities {
+			if i > 0 {
+				select {
+				case <-limiter.C:
+				case <-ctx.Done():
+				}
+			}
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+			}
+			if ctx.Err() != nil {
+				break
+			}
+			wg.Add(1)
+
+			go func(city string) {
+				defer wg.Done()
+				defer func() { <-slots }()
+
+				weather, err := fetch(city)
+				if err != nil {
+					slog.Warn("Preload failed", "city", city, "err", err)
+					progress.failed.Add(1)
+				} else {
+					cache.Set(cacheKey(city, weather.Unit), weather, cacheTTL)
+				}
+				progress.done.Add(1)
+			}(city)
+		}
+
+		wg.Wait()
+	}()
+
+	return progress
+}
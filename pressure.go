@@ -0,0 +1,54 @@
+package main
+
+import (
+	"strings"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// PRESSURE CHANGES WITHIN PRESSURE_STEADY_HPA BETWEEN FETCHES COUNT AS STEADY
+const PRESSURE_STEADY_HPA int = 1
+
+// Trend is the direction of a reading between two consecutive fetches
+type Trend int
+
+const (
+	TrendUnknown Trend = iota
+	TrendSteady
+	TrendRising
+	TrendFalling
+)
+
+// pressureTrend compares current with the previous fetch for the same panel.
+// A different city, or a missing reading on either side, gives TrendUnknown,
+// so the trend starts over whenever the panel switches cities.
+func pressureTrend(previous, current WeatherData) Trend {
+	if previous.Pressure == 0 || current.Pressure == 0 || !strings.EqualFold(previous.Location, current.Location) {
+		return TrendUnknown
+	}
+
+	delta := current.Pressure - previous.Pressure
+	switch {
+	case delta > PRESSURE_STEADY_HPA:
+		return TrendRising
+	case delta < -PRESSURE_STEADY_HPA:
+		return TrendFalling
+	default:
+		return TrendSteady
+	}
+}
+
+// drawTrendArrow draws an up, down or right-pointing arrow filling the size
+// by size box at origin. Unknown trends draw nothing.
+func drawTrendArrow(trend Trend, origin rl.Vector2, size float32, color rl.Color) {
+	x, y := origin.X, origin.Y
+
+	switch trend {
+	case TrendRising:
+		rl.DrawTriangle(rl.NewVector2(x+size/2, y), rl.NewVector2(x, y+size), rl.NewVector2(x+size, y+size), color)
+	case TrendFalling:
+		rl.DrawTriangle(rl.NewVector2(x+size/2, y+size), rl.NewVector2(x+size, y), rl.NewVector2(x, y), color)
+	case TrendSteady:
+		rl.DrawTriangle(rl.NewVector2(x+size, y+size/2), rl.NewVector2(x, y), rl.NewVector2(x, y+size), color)
+	}
+}
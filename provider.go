@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// PROVIDER NAMES ACCEPTED BY THE PROVIDER CONFIG VALUE
+const (
+	PROVIDER_OPENWEATHERMAP = "openweathermap"
+	PROVIDER_OPEN_METEO     = "open-meteo"
+)
+
+// Provider is one weather service. The UI only ever sees WeatherData,
+// ForecastEntry and GeoLocation, so it looks the same whichever is set.
+type Provider interface {
+	// Fetch returns the current weather for a city name or "lat,lon" pair
+	Fetch(ctx context.Context, city string) (WeatherData, error)
+	// Forecast returns up to FORECAST_DAYS daily entries for city
+	Forecast(ctx context.Context, city string) ([]ForecastEntry, error)
+	// Geocode lists places matching query, for the search suggestions
+	Geocode(ctx context.Context, query string) ([]GeoLocation, error)
+	// WithUnits returns a copy of the provider that reports in unit
+	WithUnits(unit Unit) Provider
+}
+
+// envProvider reads PROVIDER, defaulting to OpenWeatherMap
+func envProvider() string {
+	name := strings.ToLower(strings.TrimSpace(os.Getenv("PROVIDER")))
+	if name == "" {
+		return PROVIDER_OPENWEATHERMAP
+	}
+	return name
+}
+
+// newProvider builds the provider named by PROVIDER from config
+func newProvider(unit Unit) (Provider, error) {
+	switch name := envProvider(); name {
+	case PROVIDER_OPENWEATHERMAP:
+		return newWeatherClient(unit), nil
+	case PROVIDER_OPEN_METEO:
+		return newOpenMeteoClient(unit), nil
+	default:
+		return nil, fmt.Errorf("unknown provider %q, want %s or %s", name, PROVIDER_OPENWEATHERMAP, PROVIDER_OPEN_METEO)
+	}
+}
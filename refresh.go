@@ -0,0 +1,74 @@
+package main
+
+import (
+	"log/slog"
+	"math/rand"
+	"os"
+	"strconv"
+	"time"
+)
+
+// AUTO-REFRESH RUNS EVERY REFRESH_INTERVAL, 10 MINUTES UNLESS CONFIGURED
+const (
+	REFRESH_INTERVAL_DEFAULT = 10 * time.Minute
+	REFRESH_BACKOFF_CAP      = time.Hour
+)
+
+// refreshBackoff is the error budget for auto-refresh. Each consecutive
+// failure doubles the refresh interval, up to REFRESH_BACKOFF_CAP, so an
+// offline machine stops retrying every cycle. A success resets it.
+type refreshBackoff struct {
+	failures int
+}
+
+func (b *refreshBackoff) Failure() {
+	b.failures++
+}
+
+func (b *refreshBackoff) Success() {
+	b.failures = 0
+}
+
+// BackedOff reports whether the interval is currently stretched
+func (b *refreshBackoff) BackedOff() bool {
+	return b.failures > 0
+}
+
+// Interval returns the base interval scaled by the current failure streak
+func (b *refreshBackoff) Interval(base time.Duration) time.Duration {
+	interval := base
+	for i := 0; i < b.failures && interval < REFRESH_BACKOFF_CAP; i++ {
+		interval *= 2
+	}
+	return min(interval, REFRESH_BACKOFF_CAP)
+}
+
+// REFRESH_JITTER SPREADS EACH REFRESH BY UP TO ±10% SO MANY INSTANCES
+// STARTED TOGETHER DON'T ALL HIT THE API AT THE SAME MOMENT
+const (
+	REFRESH_JITTER_DEFAULT float64 = 0.1
+	REFRESH_JITTER_MAX     float64 = 0.5
+)
+
+// refreshJitter reads REFRESH_JITTER as a fraction of the interval
+func refreshJitter() float64 {
+	value := os.Getenv("REFRESH_JITTER")
+	if value == "" {
+		return REFRESH_JITTER_DEFAULT
+	}
+
+	jitter, err := strconv.ParseFloat(value, 64)
+	if err != nil || jitter < 0 || jitter > REFRESH_JITTER_MAX {
+		slog.Warn("REFRESH_JITTER is out of range, using the default", "value", value, "max", REFRESH_JITTER_MAX, "default", REFRESH_JITTER_DEFAULT)
+		return REFRESH_JITTER_DEFAULT
+	}
+	return jitter
+}
+
+// nextRefresh picks the next refresh time, randomized by ±jitter of interval.
+// It is drawn fresh every cycle so instances drift apart rather than staying
+// in lockstep.
+func nextRefresh(now time.Time, interval time.Duration, jitter float64) time.Time {
+	offset := (rand.Float64()*2 - 1) * jitter * float64(interval)
+	return now.Add(interval + time.Duration(offset))
+}
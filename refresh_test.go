@@ -0,0 +1,53 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRefreshBackoffDoubles(t *testing.T) {
+	var backoff refreshBackoff
+	want := []time.Duration{10 * time.Minute, 20 * time.Minute, 40 * time.Minute, time.Hour, time.Hour}
+	for i, interval := range want {
+		if got := backoff.Interval(10 * time.Minute); got != interval {
+			t.Errorf("after %d failures: Interval = %v, want %v", i, got, interval)
+		}
+		backoff.Failure()
+	}
+}
+
+func TestRefreshBackoffReset(t *testing.T) {
+	base := 10 * time.Minute
+
+	var backoff refreshBackoff
+	if backoff.BackedOff() {
+		t.Error("BackedOff = true before any failure")
+	}
+
+	for range 100 {
+		backoff.Failure()
+	}
+	if !backoff.BackedOff() || backoff.Interval(base) != REFRESH_BACKOFF_CAP {
+		t.Errorf("after a long streak: BackedOff %v, Interval %v; want true, the cap", backoff.BackedOff(), backoff.Interval(base))
+	}
+
+	backoff.Success()
+	if backoff.BackedOff() || backoff.Interval(base) != base {
+		t.Errorf("after Success: BackedOff %v, Interval %v; want false, %v", backoff.BackedOff(), backoff.Interval(base), base)
+	}
+}
+
+func TestNextRefreshJitter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	interval := 10 * time.Minute
+
+	if at := nextRefresh(now, interval, 0); !at.Equal(now.Add(interval)) {
+		t.Errorf("no jitter: next refresh at %v, want exactly %v", at, now.Add(interval))
+	}
+	for range 1000 {
+		wait := nextRefresh(now, interval, REFRESH_JITTER_DEFAULT).Sub(now)
+		if wait < 9*time.Minute || wait > 11*time.Minute {
+			t.Fatalf("wait %v is outside ±10%% of %v", wait, interval)
+		}
+	}
+}
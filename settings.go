@@ -0,0 +1,182 @@
+package main
+
+import (
+	"math"
+	"os"
+	"slices"
+	"time"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// SETTINGS OVERLAY GEOMETRY. THE REFRESH STEPPER MOVES IN WHOLE MINUTES
+// BETWEEN SETTINGS_INTERVAL_MIN AND SETTINGS_INTERVAL_MAX.
+const (
+	SETTINGS_WIDTH        float32 = 380
+	SETTINGS_ROW_HEIGHT   float32 = 40
+	SETTINGS_PADDING      float32 = 20
+	SETTINGS_CONTROL      float32 = 130
+	SETTINGS_STEP_BUTTON  float32 = 32
+	GEAR_TEETH                    = 8
+	SETTINGS_INTERVAL_MIN         = time.Minute
+	SETTINGS_INTERVAL_MAX         = time.Hour
+)
+
+// settingsLangs are the languages the settings overlay cycles through
+var settingsLangs = []string{"en", "de", "es", "fr", "it", "pt"}
+
+// Setting is what a click in the settings overlay asks to change
+type Setting int
+
+const (
+	SettingNone Setting = iota
+	SettingUnits
+	SettingWind
+	SettingTheme
+	SettingAutoRefresh
+	SettingIntervalDown
+	SettingIntervalUp
+	SettingLang
+	SettingClose
+)
+
+// settingsRow is one line of the overlay. A row with Decrease set is a
+// stepper with "-" and "+" buttons; any other row toggles on a click.
+type settingsRow struct {
+	Label    string
+	Value    string
+	Increase Setting
+	Decrease Setting
+}
+
+// applySettings exports the settings saved from the overlay to the
+// environment, where the rest of the app reads them, much like Config.Apply.
+// A value changed in the app wins over .env and config.json.
+func applySettings(state appState) {
+	for key, value := range map[string]string{
+		"UNITS":            state.Units,
+		"WIND_UNIT":        state.WindUnit,
+		"REFRESH_INTERVAL": state.RefreshInterval,
+		"LANG":             state.Lang,
+	} {
+		if value != "" {
+			os.Setenv(key, value)
+		}
+	}
+	windUnit = envWindUnit()
+}
+
+// nextLang returns the language after lang in settingsLangs, starting over
+// at English for a language not in the list
+func nextLang(lang string) string {
+	index := slices.Index(settingsLangs, lang)
+	return settingsLangs[(index+1)%len(settingsLangs)]
+}
+
+// stepInterval moves interval by steps whole minutes, kept within the
+// stepper's range
+func stepInterval(interval time.Duration, steps int) time.Duration {
+	minutes := time.Duration(math.Round(interval.Minutes())) + time.Duration(steps)
+	return min(max(minutes*time.Minute, SETTINGS_INTERVAL_MIN), SETTINGS_INTERVAL_MAX)
+}
+
+// settingsBounds centers the overlay for rows rows in the window
+func settingsBounds(screenWidth, screenHeight float32, rows int) rl.Rectangle {
+	height := 2*SETTINGS_PADDING + 30 + float32(rows)*SETTINGS_ROW_HEIGHT
+	return rl.NewRectangle((screenWidth-SETTINGS_WIDTH)/2, (screenHeight-height)/2, SETTINGS_WIDTH, height)
+}
+
+func settingsRowRect(bounds rl.Rectangle, index int) rl.Rectangle {
+	return rl.NewRectangle(
+		bounds.X+SETTINGS_PADDING,
+		bounds.Y+SETTINGS_PADDING+30+float32(index)*SETTINGS_ROW_HEIGHT,
+		bounds.Width-2*SETTINGS_PADDING,
+		SETTINGS_ROW_HEIGHT,
+	)
+}
+
+// settingsControls returns the clickable parts of a row: the value box for a
+// toggle, or the "-" and "+" buttons and the value between them for a stepper
+func settingsControls(row rl.Rectangle) (value, decrease, increase rl.Rectangle) {
+	value = rl.NewRectangle(row.X+row.Width-SETTINGS_CONTROL, row.Y+5, SETTINGS_CONTROL, row.Height-10)
+	decrease = rl.NewRectangle(value.X, value.Y, SETTINGS_STEP_BUTTON, value.Height)
+	increase = rl.NewRectangle(value.X+value.Width-SETTINGS_STEP_BUTTON, value.Y, SETTINGS_STEP_BUTTON, value.Height)
+	return value, decrease, increase
+}
+
+// settingsClicked returns the setting clicked this frame. A click outside
+// the overlay closes it.
+func settingsClicked(bounds rl.Rectangle, rows []settingsRow) Setting {
+	if !rl.IsMouseButtonPressed(rl.MouseLeftButton) {
+		return SettingNone
+	}
+	if !rl.CheckCollisionPointRec(rl.GetMousePosition(), bounds) {
+		return SettingClose
+	}
+
+	for i, row := range rows {
+		value, decrease, increase := settingsControls(settingsRowRect(bounds, i))
+		if row.Decrease == SettingNone {
+			if buttonClicked(value) {
+				return row.Increase
+			}
+			continue
+		}
+		if buttonClicked(decrease) {
+			return row.Decrease
+		}
+		if buttonClicked(increase) {
+			return row.Increase
+		}
+	}
+	return SettingNone
+}
+
+// drawSettings draws the overlay over a dimmed window
+func drawSettings(font rl.Font, theme Theme, bounds rl.Rectangle, rows []settingsRow) {
+	rl.DrawRectangle(0, 0, int32(rl.GetScreenWidth()), int32(rl.GetScreenHeight()), rl.Fade(theme.Background, 0.6))
+	rl.DrawRectangleRounded(bounds, 0.05, 8, theme.Box)
+	rl.DrawRectangleRoundedLinesEx(bounds, 0.05, 8, 2, theme.Text)
+
+	rl.DrawTextEx(font, "Settings", rl.NewVector2(bounds.X+SETTINGS_PADDING, bounds.Y+SETTINGS_PADDING-4), 24, 0, theme.Accent)
+
+	for i, row := range rows {
+		rect := settingsRowRect(bounds, i)
+		rl.DrawTextEx(font, row.Label, rl.NewVector2(rect.X, rect.Y+11), 18, 0, theme.Text)
+
+		value, decrease, increase := settingsControls(rect)
+		if row.Decrease == SettingNone {
+			drawButton(font, theme, value, row.Value, true)
+			continue
+		}
+
+		drawButton(font, theme, decrease, "-", true)
+		drawButton(font, theme, increase, "+", true)
+		size := rl.MeasureTextEx(font, row.Value, 18, 0)
+		rl.DrawTextEx(
+			font,
+			row.Value,
+			rl.NewVector2(value.X+(value.Width-size.X)/2, value.Y+(value.Height-size.Y)/2),
+			18, 0, theme.Text,
+		)
+	}
+}
+
+// drawGear draws the settings button as a cog, lightened on hover
+func drawGear(theme Theme, bounds rl.Rectangle) {
+	color := theme.Muted
+	if rl.CheckCollisionPointRec(rl.GetMousePosition(), bounds) {
+		color = theme.Accent
+	}
+
+	center := rl.NewVector2(bounds.X+bounds.Width/2, bounds.Y+bounds.Height/2)
+	radius := min(bounds.Width, bounds.Height) / 2
+
+	// EACH BAR CROSSES THE CENTER, MAKING TWO OPPOSITE TEETH
+	for i := range GEAR_TEETH / 2 {
+		tooth := rl.NewRectangle(center.X, center.Y, radius*0.4, radius*2)
+		rl.DrawRectanglePro(tooth, rl.NewVector2(tooth.Width/2, radius), float32(i)*360/GEAR_TEETH, color)
+	}
+	rl.DrawCircleV(center, radius*0.75, color)
+	rl.DrawCircleV(center, radius*0.3, theme.Background)
+}
@@ -0,0 +1,60 @@
+package main
+
+import rl "github.com/gen2brain/raylib-go/raylib"
+
+type severity int
+
+const (
+	severityNone severity = iota
+	severityWarning
+	severitySevere
+)
+
+// TEMPERATURES BEYOND THESE ARE FLAGGED REGARDLESS OF CONDITION
+const (
+	SEVERE_HEAT_CELSIUS int = 40
+	SEVERE_COLD_CELSIUS int = -25
+)
+
+// conditionSeverity grades the weather from its OpenWeather condition code
+// (https://openweathermap.org/weather-conditions) and the temperature
+func conditionSeverity(weather WeatherData) severity {
+	code := weather.ConditionID
+	celsius := weather.Unit.ToCelsius(weather.Temperature)
+
+	switch {
+	case code >= 200 && code < 300, code == 771, code == 781:
+		// THUNDERSTORM, SQUALLS, TORNADO
+		return severitySevere
+	case celsius >= SEVERE_HEAT_CELSIUS, celsius <= SEVERE_COLD_CELSIUS:
+		return severitySevere
+	case code == 502, code == 503, code == 504, code == 511, code == 522,
+		code == 602, code == 622, code == 762:
+		// HEAVY RAIN, FREEZING RAIN, HEAVY SNOW, VOLCANIC ASH
+		return severityWarning
+	default:
+		return severityNone
+	}
+}
+
+// drawSeverityAccent outlines the panel in a steady warning color. It never
+// flashes, so it stays calm for users sensitive to motion.
+func drawSeverityAccent(font rl.Font, theme Theme, level severity, panel rl.Rectangle) {
+	if level == severityNone {
+		return
+	}
+
+	color, label := theme.Caution, "WEATHER WARNING"
+	if level == severitySevere {
+		color, label = theme.Warn, "SEVERE WEATHER"
+	}
+
+	rl.DrawRectangleLinesEx(panel, 4, color)
+
+	labelWidth := rl.MeasureTextEx(font, label, 16, 0).X
+	rl.DrawTextEx(
+		font,
+		label,
+		rl.NewVector2(panel.X+panel.Width-labelWidth-12, panel.Y+panel.Height-26), 16, 0, color,
+	)
+}
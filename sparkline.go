@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// SPARKLINE_POINTS IS HOW MANY LOGGED TEMPERATURES THE SPARKLINE SHOWS
+const (
+	SPARKLINE_POINTS      int     = 12
+	SPARKLINE_LABEL_WIDTH float32 = 60
+)
+
+// sparklineHistory caches the logged temperatures for the shown weather, so
+// the CSV log is only read again after a new fetch or a unit change
+type sparklineHistory struct {
+	key   string
+	temps []int
+}
+
+func (h *sparklineHistory) For(log *csvLog, weather WeatherData) []int {
+	key := fmt.Sprintf("%s|%d|%s", weather.Location, weather.FetchedAt.UnixNano(), weather.Unit.APIParam())
+	if key == h.key {
+		return h.temps
+	}
+
+	temps, err := log.Recent(weather.Location, SPARKLINE_POINTS, weather.Unit)
+	if err != nil {
+		slog.Warn("Sparkline history not read", "err", err)
+	}
+	h.key, h.temps = key, temps
+	return temps
+}
+
+// drawSparkline draws temps as a polyline scaled into bounds, with the high
+// and low labelled on the right. Fewer than two points draw a note instead.
+func drawSparkline(font rl.Font, theme Theme, temps []int, unit Unit, bounds rl.Rectangle) {
+	if len(temps) < 2 {
+		rl.DrawTextEx(font, "not enough history yet", rl.NewVector2(bounds.X, bounds.Y), 14, 0, theme.Muted)
+		return
+	}
+
+	low, high := temps[0], temps[0]
+	for _, temp := range temps {
+		low, high = min(low, temp), max(high, temp)
+	}
+
+	// A FLAT HISTORY IS DRAWN ACROSS THE MIDDLE
+	span := float32(high - low)
+	plot := rl.NewRectangle(bounds.X, bounds.Y, bounds.Width-SPARKLINE_LABEL_WIDTH, bounds.Height)
+	point := func(i int) rl.Vector2 {
+		y := plot.Y + plot.Height/2
+		if span > 0 {
+			y = plot.Y + plot.Height*(1-float32(temps[i]-low)/span)
+		}
+		return rl.NewVector2(plot.X+plot.Width*float32(i)/float32(len(temps)-1), y)
+	}
+
+	for i := 1; i < len(temps); i++ {
+		rl.DrawLineEx(point(i-1), point(i), 2, theme.Accent)
+	}
+
+	labelX := plot.X + plot.Width + 8
+	rl.DrawTextEx(font, formatTemperature(high, unit), rl.NewVector2(labelX, plot.Y-2), 14, 0, theme.Muted)
+	rl.DrawTextEx(font, formatTemperature(low, unit), rl.NewVector2(labelX, plot.Y+plot.Height-12), 14, 0, theme.Muted)
+}
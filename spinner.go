@@ -0,0 +1,17 @@
+package main
+
+import rl "github.com/gen2brain/raylib-go/raylib"
+
+// SPINNER_SPEED IS IN DEGREES PER SECOND, SO THE SPIN IS THE SAME AT ANY FPS
+const (
+	SPINNER_RADIUS float32 = 7
+	SPINNER_SPEED  float32 = 360
+	SPINNER_ARC    float32 = 270
+)
+
+// drawSpinner draws a rotating arc centered on center, with the angle taken
+// from the raylib clock rather than a frame count
+func drawSpinner(center rl.Vector2, color rl.Color) {
+	start := float32(rl.GetTime()) * SPINNER_SPEED
+	rl.DrawRing(center, SPINNER_RADIUS-2, SPINNER_RADIUS, start, start+SPINNER_ARC, 24, color)
+}
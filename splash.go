@@ -0,0 +1,75 @@
+package main
+
+import (
+	"os"
+	"strconv"
+	"time"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+const SPLASH_FADE = 500 * time.Millisecond
+
+// isSplashEnabled reports whether the startup splash is shown. It is on
+// unless SPLASH is set to a false value.
+func isSplashEnabled() bool {
+	value := os.Getenv("SPLASH")
+	if value == "" {
+		return true
+	}
+	enabled, _ := strconv.ParseBool(value)
+	return enabled
+}
+
+// isReduceMotion reports whether REDUCE_MOTION asks for animations to be skipped
+func isReduceMotion() bool {
+	enabled, _ := strconv.ParseBool(os.Getenv("REDUCE_MOTION"))
+	return enabled
+}
+
+// drawSplash covers the window with the logo and a loading line. alpha fades
+// the whole splash so it can dissolve into the UI underneath.
+func drawSplash(font rl.Font, theme Theme, alpha float32) {
+	if alpha <= 0 {
+		return
+	}
+
+	width, height := float32(rl.GetScreenWidth()), float32(rl.GetScreenHeight())
+
+	rl.DrawRectangle(0, 0, int32(width), int32(height), rl.Fade(theme.Background, alpha))
+
+	title := "Go Weather"
+	titleSize := rl.MeasureTextEx(font, title, 48, 0)
+	rl.DrawTextEx(
+		font,
+		title,
+		rl.NewVector2((width-titleSize.X)/2, height/2-titleSize.Y), 48, 0, rl.Fade(theme.Accent, alpha),
+	)
+
+	loading := "Loading..."
+	loadingSize := rl.MeasureTextEx(font, loading, 20, 0)
+	rl.DrawTextEx(
+		font,
+		loading,
+		rl.NewVector2((width-loadingSize.X)/2, height/2+10), 20, 0, rl.Fade(theme.Muted, alpha),
+	)
+}
+
+// presentSplash draws a single splash frame so the window isn't blank while
+// startup work blocks the loop
+func presentSplash(font rl.Font, theme Theme) {
+	rl.BeginDrawing()
+	rl.ClearBackground(theme.Background)
+	drawSplash(font, theme, 1)
+	rl.EndDrawing()
+}
+
+// splashAlpha is the splash opacity while it fades out after startup. With
+// reduce motion it disappears at once instead of fading.
+func splashAlpha(fadeEnd time.Time, reduceMotion bool) float32 {
+	remaining := time.Until(fadeEnd)
+	if reduceMotion || remaining <= 0 {
+		return 0
+	}
+	return float32(remaining) / float32(SPLASH_FADE)
+}
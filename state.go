@@ -0,0 +1,104 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+)
+
+// STATE_DIR AND STATE_FILE LIVE UNDER THE USER'S HOME DIRECTORY
+const (
+	STATE_DIR  = ".go-weather"
+	STATE_FILE = "state.json"
+)
+
+// appState is what the app remembers between launches
+type appState struct {
+	LastCity    string   `json:"last_city"`
+	Favorites   []string `json:"favorites"`
+	History     []string `json:"history"`
+	DarkMode    bool     `json:"dark_mode"`
+	HideHelpers bool     `json:"hide_helpers"`
+
+	// SET FROM THE SETTINGS OVERLAY; EMPTY MEANS THE CONFIG VALUE APPLIES
+	Units           string `json:"units,omitempty"`
+	WindUnit        string `json:"wind_unit,omitempty"`
+	RefreshInterval string `json:"refresh_interval,omitempty"`
+	Lang            string `json:"lang,omitempty"`
+	AutoRefresh     bool   `json:"auto_refresh"`
+
+	// Labels are display names for favorites, keyed by normalized city
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// clone copies s with its own slices and map, so a save goroutine can encode
+// it while the render loop keeps changing the original
+func (s appState) clone() appState {
+	s.Favorites = slices.Clone(s.Favorites)
+	s.History = slices.Clone(s.History)
+	s.Labels = maps.Clone(s.Labels)
+	return s
+}
+
+// stateMu serializes writes, since saves run on their own goroutines
+var stateMu sync.Mutex
+
+func statePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate home dir: %v", err)
+	}
+	return filepath.Join(home, STATE_DIR, STATE_FILE), nil
+}
+
+// loadState reads the saved state. A missing or corrupt file gives an empty
+// state, so the app just starts fresh.
+func loadState() appState {
+	var state appState
+
+	path, err := statePath()
+	if err != nil {
+		return state
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return state
+	}
+
+	if err := json.Unmarshal(data, &state); err != nil {
+		return appState{}
+	}
+	return state
+}
+
+// saveState writes state through a temp file, so a crash mid-write never
+// leaves a truncated file behind
+func saveState(state appState) error {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+
+	path, err := statePath()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create state dir: %v", err)
+	}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode state: %v", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write state: %v", err)
+	}
+	return os.Rename(tmp, path)
+}
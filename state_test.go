@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestAppStateClone(t *testing.T) {
+	state := appState{
+		Favorites: []string{"Paris"},
+		History:   []string{"Paris", "Lyon"},
+		Labels:    map[string]string{"paris": "Home"},
+	}
+	saved := state.clone()
+
+	state.Favorites[0] = "Oslo"
+	state.History[1] = "Oslo"
+	state.Labels = setFavoriteLabel(state.Labels, "Paris", "")
+	state.Labels = setFavoriteLabel(state.Labels, "Oslo", "Work")
+
+	if saved.Favorites[0] != "Paris" || saved.History[1] != "Lyon" {
+		t.Errorf("clone shares slices: %v %v", saved.Favorites, saved.History)
+	}
+	if len(saved.Labels) != 1 || saved.Labels["paris"] != "Home" {
+		t.Errorf("clone shares labels: %v", saved.Labels)
+	}
+}
+
+// TestSaveStateWhileEditing saves clones on goroutines, as saveStateAsync
+// does, while labels keep changing. Run it with -race.
+func TestSaveStateWhileEditing(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	state := appState{Favorites: []string{"Paris"}}
+	var saves sync.WaitGroup
+	for i := range 50 {
+		state.Labels = setFavoriteLabel(state.Labels, "Paris", fmt.Sprint("Label ", i))
+		saves.Add(1)
+		go func(saved appState) {
+			defer saves.Done()
+			if err := saveState(saved); err != nil {
+				t.Error(err)
+			}
+		}(state.clone())
+	}
+	saves.Wait()
+
+	if got := loadState(); got.Labels["paris"] == "" {
+		t.Errorf("saved labels = %v, want one for Paris", got.Labels)
+	}
+}
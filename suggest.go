@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// SUGGESTIONS WAIT FOR A PAUSE IN TYPING, SO THERE IS NO REQUEST PER KEYSTROKE
+const (
+	SUGGEST_DEBOUNCE   = 300 * time.Millisecond
+	SUGGEST_MIN_CHARS  = 3
+	SUGGEST_ROW_HEIGHT = 24
+)
+
+type suggestResult struct {
+	query     string
+	locations []GeoLocation
+}
+
+// suggester looks up city names for the input box as the user types. Only
+// one lookup runs at a time; a change to the input cancels it.
+type suggester struct {
+	cache     *geocodeCache
+	results   chan suggestResult
+	cancel    context.CancelFunc
+	input     string
+	changedAt time.Time
+	requested string
+
+	Suggestions []GeoLocation
+}
+
+func newSuggester(cache *geocodeCache) *suggester {
+	return &suggester{cache: cache, results: make(chan suggestResult, 1)}
+}
+
+// Update tracks input and starts a debounced lookup once typing pauses.
+// Call it every frame; it never blocks.
+func (s *suggester) Update(ctx context.Context, input string, now time.Time) {
+	input = strings.TrimSpace(input)
+	if input != s.input {
+		s.input = input
+		s.changedAt = now
+		s.Suggestions = nil
+		s.stop()
+	}
+
+	if input != s.requested && len([]rune(input)) >= SUGGEST_MIN_CHARS && !isCoords(input) && now.Sub(s.changedAt) >= SUGGEST_DEBOUNCE {
+		s.requested = input
+
+		lookupCtx, cancel := context.WithCancel(ctx)
+		s.cancel = cancel
+		go func(query string) {
+			locations, err := s.cache.Lookup(lookupCtx, query)
+			if err != nil {
+				return
+			}
+			select {
+			case s.results <- suggestResult{query: query, locations: locations}:
+			case <-lookupCtx.Done():
+			}
+		}(input)
+	}
+
+	select {
+	case result := <-s.results:
+		if result.query == s.input {
+			s.Suggestions = result.locations
+		}
+	default:
+	}
+}
+
+// Commit hides the dropdown and treats text as already looked up, so
+// putting a chosen suggestion into the input box does not suggest again
+func (s *suggester) Commit(text string) {
+	s.stop()
+	s.input = strings.TrimSpace(text)
+	s.requested = s.input
+	s.Suggestions = nil
+}
+
+func (s *suggester) stop() {
+	if s.cancel != nil {
+		s.cancel()
+		s.cancel = nil
+	}
+}
+
+func suggestionRow(below rl.Rectangle, index int) rl.Rectangle {
+	return rl.NewRectangle(below.X, below.Y+below.Height+float32(index*SUGGEST_ROW_HEIGHT), below.Width, SUGGEST_ROW_HEIGHT)
+}
+
+// clickedSuggestion returns the index of the suggestion clicked this frame,
+// or -1
+func clickedSuggestion(suggestions []GeoLocation, below rl.Rectangle) int {
+	for i := range suggestions {
+		if buttonClicked(suggestionRow(below, i)) {
+			return i
+		}
+	}
+	return -1
+}
+
+// drawSuggestions draws the dropdown under the below rectangle, highlighting
+// the hovered row
+func drawSuggestions(font rl.Font, theme Theme, suggestions []GeoLocation, below rl.Rectangle) {
+	mouse := rl.GetMousePosition()
+
+	for i, location := range suggestions {
+		row := suggestionRow(below, i)
+
+		fill := theme.Box
+		if rl.CheckCollisionPointRec(mouse, row) {
+			fill = theme.InputBox
+		}
+		rl.DrawRectangleRec(row, fill)
+		rl.DrawRectangleLinesEx(row, 1, theme.Muted)
+
+		rl.DrawTextEx(
+			font,
+			truncateText(font, location.Label(), 18, row.Width-10),
+			rl.NewVector2(row.X+5, row.Y+3), 18, 0, theme.Text,
+		)
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// WINDOWS NARROWER THAN THIS SHOW THE SUMMARY LINE AUTOMATICALLY
+const NARROW_WIDTH int32 = 600
+
+// formatSummary renders the weather as one dense line, e.g.
+// "London 18°C Cloudy 72% 43.2 km/h". Fields that are empty are left out.
+func formatSummary(weather WeatherData) string {
+	parts := []string{
+		weather.Location,
+		formatTemperature(weather.Temperature, weather.Unit),
+		conditionText(weather),
+		fmt.Sprintf("%d%%", weather.Humidity),
+		formatWind(weather.WindSpeed, weather.Unit),
+	}
+
+	var line []string
+	for _, part := range parts {
+		if part != "" {
+			line = append(line, part)
+		}
+	}
+	return strings.Join(line, " ")
+}
+
+// formatClipboard renders the weather as a sentence for pasting into chat, e.g.
+// "London: 12°C (feels 10°C), Clouds, humidity 80%, wind 15.0 km/h"
+func formatClipboard(weather WeatherData) string {
+	return fmt.Sprintf("%s: %s (feels %s), %s, humidity %d%%, wind %s",
+		weather.Location,
+		formatTemperature(weather.Temperature, weather.Unit),
+		formatTemperature(weather.FeelsLike, weather.Unit),
+		conditionText(weather),
+		weather.Humidity,
+		formatWind(weather.WindSpeed, weather.Unit),
+	)
+}
+
+// isSummaryAlways reports whether SUMMARY_LINE asks for the summary at every width
+func isSummaryAlways() bool {
+	enabled, _ := strconv.ParseBool(os.Getenv("SUMMARY_LINE"))
+	return enabled
+}
@@ -0,0 +1,37 @@
+package main
+
+import rl "github.com/gen2brain/raylib-go/raylib"
+
+// tempStop pins a color to a Celsius temperature
+type tempStop struct {
+	celsius int
+	color   rl.Color
+}
+
+// tempPalette runs cold to hot. Temperatures between stops blend the two
+// neighbors; beyond the ends they take the end color.
+var tempPalette = []tempStop{
+	{-20, rl.NewColor(40, 90, 220, 255)},
+	{0, rl.NewColor(60, 160, 235, 255)},
+	{15, rl.NewColor(50, 170, 80, 255)},
+	{25, rl.NewColor(240, 150, 30, 255)},
+	{35, rl.NewColor(220, 40, 40, 255)},
+}
+
+// tempColor maps a Celsius temperature onto tempPalette. Callers convert
+// first, so the color for a reading does not depend on the display unit.
+func tempColor(celsius int) rl.Color {
+	if celsius <= tempPalette[0].celsius {
+		return tempPalette[0].color
+	}
+
+	for i := 1; i < len(tempPalette); i++ {
+		low, high := tempPalette[i-1], tempPalette[i]
+		if celsius <= high.celsius {
+			t := float32(celsius-low.celsius) / float32(high.celsius-low.celsius)
+			return lerpColor(low.color, high.color, t)
+		}
+	}
+
+	return tempPalette[len(tempPalette)-1].color
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+func TestTempColor(t *testing.T) {
+	tests := []struct {
+		celsius int
+		want    rl.Color
+	}{
+		{-45, rl.NewColor(40, 90, 220, 255)},
+		{-20, rl.NewColor(40, 90, 220, 255)},
+		{-10, rl.NewColor(50, 125, 228, 255)},
+		{0, rl.NewColor(60, 160, 235, 255)},
+		{15, rl.NewColor(50, 170, 80, 255)},
+		{20, rl.NewColor(145, 160, 55, 255)},
+		{25, rl.NewColor(240, 150, 30, 255)},
+		{35, rl.NewColor(220, 40, 40, 255)},
+		{50, rl.NewColor(220, 40, 40, 255)},
+	}
+	for _, test := range tests {
+		if got := tempColor(test.celsius); got != test.want {
+			t.Errorf("tempColor(%d) = %v, want %v", test.celsius, got, test.want)
+		}
+	}
+}
+
+// TestTempColorByUnit checks the same reading gets the same color whichever
+// unit it is shown in, since callers convert to Celsius first
+func TestTempColorByUnit(t *testing.T) {
+	for _, celsius := range []int{-30, -5, 12, 22, 31, 40} {
+		fahrenheit := Fahrenheit.FromCelsius(celsius)
+		if got, want := tempColor(Fahrenheit.ToCelsius(fahrenheit)), tempColor(celsius); got != want {
+			t.Errorf("%d°C shown as %d°F: color %v, want %v", celsius, fahrenheit, got, want)
+		}
+	}
+}
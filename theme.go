@@ -0,0 +1,151 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+const THEME_FILE string = "theme.json"
+
+// Theme maps each semantic role in the UI to a color. Success, Info and
+// Caution color status messages; Dark marks a dark background.
+type Theme struct {
+	Background rl.Color
+	Text       rl.Color
+	Muted      rl.Color
+	Strong     rl.Color
+	Accent     rl.Color
+	Input      rl.Color
+	Warn       rl.Color
+	Box        rl.Color
+	InputBox   rl.Color
+	Success    rl.Color
+	Info       rl.Color
+	Caution    rl.Color
+	Dark       bool
+}
+
+var lightTheme = Theme{
+	Background: rl.RayWhite,
+	Text:       rl.DarkGray,
+	Muted:      rl.Gray,
+	Strong:     rl.Black,
+	Accent:     rl.DarkBlue,
+	Input:      rl.Maroon,
+	Warn:       rl.Red,
+	Box:        rl.NewColor(240, 240, 240, 255),
+	InputBox:   rl.LightGray,
+	Success:    rl.NewColor(0, 150, 40, 255),
+	Info:       rl.Blue,
+	Caution:    rl.NewColor(210, 110, 0, 255),
+}
+
+// darkTheme keeps every status color light enough to read on the dark
+// background
+var darkTheme = Theme{
+	Background: rl.NewColor(28, 28, 34, 255),
+	Text:       rl.NewColor(214, 214, 220, 255),
+	Muted:      rl.NewColor(140, 140, 152, 255),
+	Strong:     rl.NewColor(245, 245, 245, 255),
+	Accent:     rl.NewColor(120, 170, 255, 255),
+	Input:      rl.NewColor(255, 150, 150, 255),
+	Warn:       rl.NewColor(255, 105, 105, 255),
+	Box:        rl.NewColor(44, 44, 54, 255),
+	InputBox:   rl.NewColor(62, 62, 76, 255),
+	Success:    rl.NewColor(90, 220, 120, 255),
+	Info:       rl.NewColor(110, 180, 255, 255),
+	Caution:    rl.NewColor(255, 175, 70, 255),
+	Dark:       true,
+}
+
+// presetTheme returns the dark or light preset
+func presetTheme(dark bool) Theme {
+	if dark {
+		return darkTheme
+	}
+	return lightTheme
+}
+
+// roles returns the theme fields by their theme.json key
+func (t *Theme) roles() map[string]*rl.Color {
+	return map[string]*rl.Color{
+		"background": &t.Background,
+		"text":       &t.Text,
+		"muted":      &t.Muted,
+		"strong":     &t.Strong,
+		"accent":     &t.Accent,
+		"input":      &t.Input,
+		"warn":       &t.Warn,
+		"box":        &t.Box,
+		"input_box":  &t.InputBox,
+		"success":    &t.Success,
+		"info":       &t.Info,
+		"caution":    &t.Caution,
+	}
+}
+
+// parseHexColor accepts #RRGGBB or #RRGGBBAA
+func parseHexColor(hex string) (rl.Color, error) {
+	digits := strings.TrimPrefix(hex, "#")
+	if len(digits) == 6 {
+		digits += "ff"
+	}
+	if len(digits) != 8 {
+		return rl.Color{}, fmt.Errorf("%q is not #RRGGBB or #RRGGBBAA", hex)
+	}
+
+	value, err := strconv.ParseUint(digits, 16, 32)
+	if err != nil {
+		return rl.Color{}, fmt.Errorf("%q is not a hex color", hex)
+	}
+
+	return rl.NewColor(uint8(value>>24), uint8(value>>16), uint8(value>>8), uint8(value)), nil
+}
+
+// loadThemeFile applies a palette file on top of base. Roles left out of the
+// file keep their base color; unknown roles and bad values are logged and
+// skipped. An example palette:
+//
+//	{
+//	  "background": "#1e1e2e",
+//	  "text":       "#cdd6f4",
+//	  "accent":     "#89b4fa",
+//	  "warn":       "#f38ba8",
+//	  "box":        "#313244"
+//	}
+func loadThemeFile(path string, base Theme) (Theme, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return base, err
+	}
+
+	var palette map[string]string
+	if err := json.Unmarshal(data, &palette); err != nil {
+		return base, fmt.Errorf("failed to parse %s: %v", path, err)
+	}
+
+	theme := base
+	roles := theme.roles()
+	for role, hex := range palette {
+		field, ok := roles[role]
+		if !ok {
+			slog.Warn("Theme: unknown role", "path", path, "role", role)
+			continue
+		}
+
+		color, err := parseHexColor(hex)
+		if err != nil {
+			slog.Warn("Theme: bad color", "path", path, "role", role, "err", err)
+			continue
+		}
+		*field = color
+	}
+
+	return theme, nil
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"time"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// TOASTS STACK UP FROM THE BOTTOM RIGHT CORNER. PAST TOAST_MAX THE OLDEST IS
+// DROPPED TO MAKE ROOM.
+const (
+	TOAST_MAX    int     = 4
+	TOAST_WIDTH  float32 = 320
+	TOAST_HEIGHT float32 = 40
+	TOAST_GAP    float32 = 8
+	TOAST_MARGIN float32 = 10
+)
+
+// toast is one error message. Like the status line it clears itself after
+// STATUS_DURATION.
+type toast struct {
+	Message string
+	Color   rl.Color
+	ClearAt time.Time
+}
+
+// toastQueue holds the toasts on screen, oldest first
+type toastQueue struct {
+	toasts []toast
+}
+
+// Push shows message as a new toast. Repeating the newest message restarts
+// its timer rather than stacking a copy.
+func (q *toastQueue) Push(message string, color rl.Color, now time.Time) {
+	if n := len(q.toasts); n > 0 && q.toasts[n-1].Message == message {
+		q.toasts[n-1].ClearAt = now.Add(STATUS_DURATION)
+		return
+	}
+
+	q.toasts = append(q.toasts, toast{Message: message, Color: color, ClearAt: now.Add(STATUS_DURATION)})
+	if len(q.toasts) > TOAST_MAX {
+		q.toasts = q.toasts[1:]
+	}
+}
+
+// Expire drops toasts whose time is up
+func (q *toastQueue) Expire(now time.Time) {
+	kept := q.toasts[:0]
+	for _, t := range q.toasts {
+		if now.Before(t.ClearAt) {
+			kept = append(kept, t)
+		}
+	}
+	q.toasts = kept
+}
+
+// toastRect places toast index, with 0 the oldest and highest up the stack
+func toastRect(index, count int, screenWidth, screenHeight float32) rl.Rectangle {
+	fromBottom := float32(count - index)
+	return rl.NewRectangle(
+		screenWidth-TOAST_WIDTH-TOAST_MARGIN,
+		screenHeight-TOAST_MARGIN-fromBottom*(TOAST_HEIGHT+TOAST_GAP)+TOAST_GAP,
+		TOAST_WIDTH, TOAST_HEIGHT,
+	)
+}
+
+// Dismiss removes the toast under a click this frame, if any
+func (q *toastQueue) Dismiss(screenWidth, screenHeight float32) {
+	if !rl.IsMouseButtonPressed(rl.MouseLeftButton) {
+		return
+	}
+
+	mouse := rl.GetMousePosition()
+	for i := range q.toasts {
+		if rl.CheckCollisionPointRec(mouse, toastRect(i, len(q.toasts), screenWidth, screenHeight)) {
+			q.toasts = append(q.toasts[:i], q.toasts[i+1:]...)
+			return
+		}
+	}
+}
+
+// Draw renders each toast as a rounded box with a "!" badge, the message and
+// an x to dismiss it
+func (q *toastQueue) Draw(font rl.Font, theme Theme, screenWidth, screenHeight float32) {
+	for i, t := range q.toasts {
+		box := toastRect(i, len(q.toasts), screenWidth, screenHeight)
+		rl.DrawRectangleRounded(box, 0.3, 6, theme.Box)
+		rl.DrawRectangleRoundedLinesEx(box, 0.3, 6, 2, t.Color)
+
+		badge := rl.NewVector2(box.X+20, box.Y+box.Height/2)
+		rl.DrawCircleV(badge, 10, t.Color)
+		rl.DrawTextEx(font, "!", rl.NewVector2(badge.X-4, badge.Y-9), 18, 0, theme.Box)
+
+		rl.DrawTextEx(font, truncateText(font, t.Message, 16, box.Width-70), rl.NewVector2(box.X+38, box.Y+12), 16, 0, theme.Text)
+		rl.DrawTextEx(font, "x", rl.NewVector2(box.X+box.Width-20, box.Y+10), 18, 0, theme.Muted)
+	}
+}
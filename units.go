@@ -0,0 +1,221 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"strings"
+)
+
+// Unit is the temperature unit requested from the API and shown in the UI
+type Unit int
+
+const (
+	Celsius Unit = iota
+	Fahrenheit
+)
+
+// APIParam is the value of the API's units= query parameter
+func (u Unit) APIParam() string {
+	if u == Fahrenheit {
+		return "imperial"
+	}
+	return "metric"
+}
+
+func (u Unit) Symbol() string {
+	if u == Fahrenheit {
+		return degreeSign + "F"
+	}
+	return degreeSign + "C"
+}
+
+func (u Unit) Toggle() Unit {
+	if u == Fahrenheit {
+		return Celsius
+	}
+	return Fahrenheit
+}
+
+// ToCelsius converts a temperature in this unit to Celsius, rounded to the
+// nearest degree
+func (u Unit) ToCelsius(temperature int) int {
+	if u == Fahrenheit {
+		return int(math.Round(float64(temperature-32) * 5 / 9))
+	}
+	return temperature
+}
+
+// FromCelsius converts a Celsius temperature to this unit, rounded to the
+// nearest degree. A Fahrenheit degree is finer than a Celsius one, so
+// ToCelsius brings the result back to celsius exactly.
+func (u Unit) FromCelsius(celsius int) int {
+	if u == Fahrenheit {
+		return int(math.Round(float64(celsius)*9/5 + 32))
+	}
+	return celsius
+}
+
+// MarshalText stores the unit by its API name so cached and exported JSON reads naturally
+func (u Unit) MarshalText() ([]byte, error) {
+	return []byte(u.APIParam()), nil
+}
+
+func (u *Unit) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "metric":
+		*u = Celsius
+	case "imperial":
+		*u = Fahrenheit
+	default:
+		return fmt.Errorf("unknown units %q", text)
+	}
+	return nil
+}
+
+// envUnit reads the starting unit from UNITS, "metric" or "imperial"
+func envUnit() Unit {
+	var unit Unit
+	if value := os.Getenv("UNITS"); value != "" {
+		if err := unit.UnmarshalText([]byte(value)); err != nil {
+			slog.Warn("UNITS not recognised, using metric", "err", err)
+		}
+	}
+	return unit
+}
+
+// formatTemperature renders a temperature with its unit suffix, e.g. "12°C"
+func formatTemperature(temperature int, unit Unit) string {
+	return fmt.Sprintf("%d%s", temperature, unit.Symbol())
+}
+
+// cacheKey keeps metric and imperial results for the same city apart
+func cacheKey(city string, unit Unit) string {
+	return normalizeCity(city) + "|" + unit.APIParam()
+}
+
+// WIND SPEED CONVERSIONS. WITH units=metric THE API REPORTS WIND IN m/s,
+// WITH units=imperial IN mph.
+const (
+	MS_TO_KMH float32 = 3.6
+	MS_TO_MPH float32 = 2.236936
+)
+
+func msToKmh(speed float32) float32 {
+	return speed * MS_TO_KMH
+}
+
+func msToMph(speed float32) float32 {
+	return speed * MS_TO_MPH
+}
+
+// WindUnit is how wind speed is shown, independent of the temperature unit
+// the data was requested in. WindAuto follows the data: km/h for metric and
+// mph for imperial.
+type WindUnit int
+
+const (
+	WindAuto WindUnit = iota
+	WindKmh
+	WindMs
+	WindMph
+)
+
+// windUnit is the wind display unit, read from WIND_UNIT in init and cycled
+// with W in the GUI
+var windUnit = WindAuto
+
+// envWindUnit reads WIND_UNIT: "kmh", "ms" or "mph". Unset follows the
+// temperature unit.
+func envWindUnit() WindUnit {
+	switch value := strings.ToLower(strings.TrimSpace(os.Getenv("WIND_UNIT"))); value {
+	case "":
+		return WindAuto
+	case "kmh", "km/h":
+		return WindKmh
+	case "ms", "m/s":
+		return WindMs
+	case "mph":
+		return WindMph
+	default:
+		slog.Warn("WIND_UNIT not recognised, following the temperature unit", "value", value)
+		return WindAuto
+	}
+}
+
+// Resolve returns the concrete unit for data requested in unit
+func (w WindUnit) Resolve(unit Unit) WindUnit {
+	if w != WindAuto {
+		return w
+	}
+	if unit == Fahrenheit {
+		return WindMph
+	}
+	return WindKmh
+}
+
+// Next cycles km/h, m/s, mph starting from what is shown for unit
+func (w WindUnit) Next(unit Unit) WindUnit {
+	switch w.Resolve(unit) {
+	case WindKmh:
+		return WindMs
+	case WindMs:
+		return WindMph
+	default:
+		return WindKmh
+	}
+}
+
+// Param is the WIND_UNIT value for w, empty for WindAuto
+func (w WindUnit) Param() string {
+	switch w {
+	case WindKmh:
+		return "kmh"
+	case WindMs:
+		return "ms"
+	case WindMph:
+		return "mph"
+	default:
+		return ""
+	}
+}
+
+func (w WindUnit) Symbol() string {
+	switch w {
+	case WindMs:
+		return "m/s"
+	case WindMph:
+		return "mph"
+	default:
+		return "km/h"
+	}
+}
+
+// convertWind converts an API wind speed, m/s for metric data and mph for
+// imperial data, to the display unit to. Every wind conversion goes through
+// here.
+func convertWind(speed float32, from Unit, to WindUnit) float32 {
+	ms := speed
+	if from == Fahrenheit {
+		ms = speed / MS_TO_MPH
+	}
+
+	switch to.Resolve(from) {
+	case WindMs:
+		return ms
+	case WindMph:
+		if from == Fahrenheit {
+			return speed
+		}
+		return msToMph(ms)
+	default:
+		return msToKmh(ms)
+	}
+}
+
+// formatWind renders the API wind speed in the wind display unit
+func formatWind(speed float32, unit Unit) string {
+	to := windUnit.Resolve(unit)
+	return fmt.Sprintf("%.1f %s", convertWind(speed, unit, to), to.Symbol())
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTemperatureConversion(t *testing.T) {
+	tests := []struct {
+		celsius, fahrenheit int
+	}{
+		{-40, -40},
+		{-18, 0},
+		{0, 32},
+		{20, 68},
+		{37, 99},
+		{100, 212},
+	}
+	for _, test := range tests {
+		if got := Fahrenheit.FromCelsius(test.celsius); got != test.fahrenheit {
+			t.Errorf("FromCelsius(%d) = %d, want %d", test.celsius, got, test.fahrenheit)
+		}
+		if got := Fahrenheit.ToCelsius(test.fahrenheit); got != test.celsius {
+			t.Errorf("ToCelsius(%d) = %d, want %d", test.fahrenheit, got, test.celsius)
+		}
+		if got := Celsius.ToCelsius(test.celsius); got != test.celsius {
+			t.Errorf("Celsius.ToCelsius(%d) = %d, want it unchanged", test.celsius, got)
+		}
+	}
+}
+
+func TestTemperatureRoundTrip(t *testing.T) {
+	for celsius := -90; celsius <= 60; celsius++ {
+		if got := Fahrenheit.ToCelsius(Fahrenheit.FromCelsius(celsius)); got != celsius {
+			t.Errorf("%d°C to °F and back = %d", celsius, got)
+		}
+	}
+}
+
+func TestWindRoundTrip(t *testing.T) {
+	for _, ms := range []float32{0, 0.5, 3.2, 10, 27.8, 45} {
+		kmh := convertWind(ms, Celsius, WindKmh)
+		if want := ms * 3.6; !near(kmh, want) {
+			t.Errorf("%v m/s = %v km/h, want %v", ms, kmh, want)
+		}
+
+		// THE SAME WIND AS IMPERIAL DATA, IN mph, MUST COME BACK TO m/s
+		mph := convertWind(ms, Celsius, WindMph)
+		if back := convertWind(mph, Fahrenheit, WindMs); !near(back, ms) {
+			t.Errorf("%v m/s to %v mph and back = %v", ms, mph, back)
+		}
+		if viaMph := convertWind(mph, Fahrenheit, WindKmh); !near(viaMph, kmh) {
+			t.Errorf("%v mph = %v km/h, want %v", mph, viaMph, kmh)
+		}
+	}
+}
+
+func TestConvertWindMatrix(t *testing.T) {
+	tests := []struct {
+		from Unit
+		to   WindUnit
+		want float32
+	}{
+		{Celsius, WindAuto, 36},
+		{Celsius, WindKmh, 36},
+		{Celsius, WindMs, 10},
+		{Celsius, WindMph, 22.36936},
+		{Fahrenheit, WindAuto, 10},
+		{Fahrenheit, WindKmh, 16.09344},
+		{Fahrenheit, WindMs, 4.4704},
+		{Fahrenheit, WindMph, 10},
+	}
+	for _, test := range tests {
+		if got := convertWind(10, test.from, test.to); !near(got, test.want) {
+			t.Errorf("convertWind(10, %v, %v) = %v, want %v", test.from, test.to, got, test.want)
+		}
+	}
+}
+
+func TestFormatWind(t *testing.T) {
+	saved := windUnit
+	t.Cleanup(func() { windUnit = saved })
+
+	tests := []struct {
+		wind WindUnit
+		unit Unit
+		want string
+	}{
+		{WindAuto, Celsius, "36.0 km/h"},
+		{WindAuto, Fahrenheit, "10.0 mph"},
+		{WindKmh, Celsius, "36.0 km/h"},
+		{WindKmh, Fahrenheit, "16.1 km/h"},
+		{WindMs, Celsius, "10.0 m/s"},
+		{WindMs, Fahrenheit, "4.5 m/s"},
+		{WindMph, Celsius, "22.4 mph"},
+		{WindMph, Fahrenheit, "10.0 mph"},
+	}
+	for _, test := range tests {
+		windUnit = test.wind
+		if got := formatWind(10, test.unit); got != test.want {
+			t.Errorf("formatWind(10, %v) with %v = %q, want %q", test.unit, test.wind, got, test.want)
+		}
+	}
+}
+
+// near compares speeds to within rounding of the conversion factors
+func near(a, b float32) bool {
+	return math.Abs(float64(a-b)) < 0.001
+}
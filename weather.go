@@ -0,0 +1,408 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OpenWeatherResponse is the subset of the API response we read. Fields
+// differ by tier, and any that are missing decode as zero values:
+//
+//	free /data/2.5/weather: name, main.temp, main.feels_like, main.humidity,
+//	  wind.speed, timezone (seconds) and weather[] at the top level
+//	paid One Call /data/3.0/onecall: no name, timezone is a zone name with
+//	  the offset in timezone_offset, and the readings live under "current"
+type OpenWeatherResponse struct {
+	Name string `json:"name"`
+	Main struct {
+		Temp      float64  `json:"temp"`
+		FeelsLike float64  `json:"feels_like"`
+		TempMin   *float64 `json:"temp_min"`
+		TempMax   *float64 `json:"temp_max"`
+		Humidity  float64  `json:"humidity"`
+		Pressure  float64  `json:"pressure"`
+	} `json:"main"`
+	Visibility *int `json:"visibility"`
+	Wind       struct {
+		Speed float64 `json:"speed"`
+		Deg   *int    `json:"deg"`
+	} `json:"wind"`
+	Sys struct {
+		Sunrise int64 `json:"sunrise"`
+		Sunset  int64 `json:"sunset"`
+	} `json:"sys"`
+	Weather  []OpenWeatherCondition `json:"weather"`
+	Timezone any                    `json:"timezone"`
+
+	TimezoneOffset int `json:"timezone_offset"`
+	Current        *struct {
+		Temp       float64                `json:"temp"`
+		FeelsLike  float64                `json:"feels_like"`
+		Humidity   float64                `json:"humidity"`
+		WindSpeed  float64                `json:"wind_speed"`
+		WindDeg    *int                   `json:"wind_deg"`
+		Pressure   float64                `json:"pressure"`
+		Visibility *int                   `json:"visibility"`
+		Sunrise    int64                  `json:"sunrise"`
+		Sunset     int64                  `json:"sunset"`
+		Weather    []OpenWeatherCondition `json:"weather"`
+	} `json:"current"`
+	// One Call has no min/max for the current reading; today's come from here
+	Daily []struct {
+		Temp struct {
+			Min float64 `json:"min"`
+			Max float64 `json:"max"`
+		} `json:"temp"`
+	} `json:"daily"`
+}
+
+type OpenWeatherCondition struct {
+	ID          int    `json:"id"`
+	Main        string `json:"main"`
+	Description string `json:"description"`
+}
+
+type WeatherData struct {
+	Location    string  `json:"location"`
+	Temperature int     `json:"temperature"`
+	Condition   string  `json:"condition"`
+	Humidity    int     `json:"humidity"`
+	WindSpeed   float32 `json:"wind_speed"`
+	FeelsLike   int     `json:"feels_like"`
+	ConditionID int     `json:"condition_id"`
+	Unit        Unit    `json:"units"`
+	// UTC offset in seconds at fetch time, DST already applied
+	TimezoneOffset int       `json:"timezone_offset"`
+	FetchedAt      time.Time `json:"fetched_at"`
+	// hPa
+	Pressure int `json:"pressure"`
+	// Meters, or VISIBILITY_UNKNOWN when the response omits it
+	Visibility int `json:"visibility"`
+	// In the city's zone; zero when the response had no sun times
+	Sunrise time.Time `json:"sunrise"`
+	Sunset  time.Time `json:"sunset"`
+	// The API's longer condition text, in the LANG language. Condition stays
+	// the English "main" value, since icons and effects are keyed on it.
+	Description string `json:"description"`
+	// Degrees the wind blows from, or WIND_DEG_UNKNOWN when the response omits it
+	WindDeg int `json:"wind_deg"`
+	// Low and high, valid only when HasTempRange is set. The free endpoint
+	// gives the spread across the city now, One Call today's forecast.
+	TempMin      int  `json:"temp_min"`
+	TempMax      int  `json:"temp_max"`
+	HasTempRange bool `json:"has_temp_range"`
+	// Change in pressure since the panel's previous fetch of this city
+	PressureTrend Trend `json:"-"`
+}
+
+// cityLocalTime converts now into the city's local time using the offset from
+// the most recent fetch. The offset is re-read on every refresh, so a DST change
+// during a long session is picked up on the next fetch.
+func cityLocalTime(now time.Time, offsetSeconds int) time.Time {
+	return now.In(time.FixedZone("", offsetSeconds))
+}
+
+// unixLocalTime converts a Unix timestamp from the API into the city's local
+// time. A zero timestamp means the field was missing and gives a zero time.
+func unixLocalTime(timestamp int64, offsetSeconds int) time.Time {
+	if timestamp == 0 {
+		return time.Time{}
+	}
+	return cityLocalTime(time.Unix(timestamp, 0), offsetSeconds)
+}
+
+// ErrCityNotFound is returned when the API has no match for the query
+var ErrCityNotFound = errors.New("city not found")
+
+// ErrEmptyQuery is returned, without any request made, for blank input
+var ErrEmptyQuery = errors.New("enter a city name")
+
+// ErrEmptyResponse and ErrMalformedResponse are wrapped when a 200 response
+// has no body or one that is not the JSON expected, as flaky proxies can send
+var (
+	ErrEmptyResponse     = errors.New("empty response")
+	ErrMalformedResponse = errors.New("malformed response")
+)
+
+// decodeResponse unmarshals an API body into v, telling an empty body apart
+// from a malformed one. A bare null counts as empty.
+func decodeResponse(body []byte, v any) error {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 || bytes.Equal(trimmed, []byte("null")) {
+		return ErrEmptyResponse
+	}
+	if err := json.Unmarshal(trimmed, v); err != nil {
+		return fmt.Errorf("%w: %v", ErrMalformedResponse, err)
+	}
+	return nil
+}
+
+// VISIBILITY_UNKNOWN MARKS A RESPONSE WITHOUT A VISIBILITY READING
+const VISIBILITY_UNKNOWN int = -1
+
+// READINGS OUTSIDE THIS CELSIUS RANGE SUGGEST THE API IS USING OTHER UNITS
+const (
+	PLAUSIBLE_MIN_CELSIUS int = -90
+	PLAUSIBLE_MAX_CELSIUS int = 60
+)
+
+func temperatureLooksWrong(celsius int) bool {
+	return celsius < PLAUSIBLE_MIN_CELSIUS || celsius > PLAUSIBLE_MAX_CELSIUS
+}
+
+// INPUT OF THE FORM "id:2643743" LOOKS UP AN OPENWEATHER CITY ID DIRECTLY, WHICH
+// AVOIDS NAME AMBIGUITY. THE PREFIX KEEPS IDS APART FROM BARE NUMERIC ZIP CODES.
+const (
+	CITY_ID_PREFIX string = "id:"
+	MAX_CITY_ID    uint64 = 9999999999
+)
+
+// parseCityID returns the ID from an "id:" query, or false if it is not a valid one
+func parseCityID(input string) (string, bool) {
+	input = strings.TrimSpace(input)
+	if len(input) <= len(CITY_ID_PREFIX) || !strings.EqualFold(input[:len(CITY_ID_PREFIX)], CITY_ID_PREFIX) {
+		return "", false
+	}
+
+	id, err := strconv.ParseUint(input[len(CITY_ID_PREFIX):], 10, 64)
+	if err != nil || id == 0 || id > MAX_CITY_ID {
+		return "", false
+	}
+	return strconv.FormatUint(id, 10), true
+}
+
+// WeatherClient fetches conditions, forecasts and places from an
+// OpenWeather-compatible API. Everything it needs is in its fields, so a test
+// or another program can point it at any server and http.Client.
+type WeatherClient struct {
+	APIKey  string
+	BaseURL string
+	// Empty derives them from BaseURL: /forecast next to /weather, and
+	// /geo/1.0/direct on the same host
+	ForecastURL string
+	GeoURL      string
+	// nil uses http.DefaultClient
+	HTTPClient *http.Client
+	Units      Unit
+	// OpenWeather language code; empty leaves the API's default
+	Lang string
+}
+
+// newWeatherClient builds a client from config: API_KEY, API_URL,
+// FORECAST_URL, GEO_URL, LANG and the shared httpClient
+func newWeatherClient(unit Unit) *WeatherClient {
+	return &WeatherClient{
+		APIKey:      os.Getenv("API_KEY"),
+		BaseURL:     os.Getenv("API_URL"),
+		ForecastURL: os.Getenv("FORECAST_URL"),
+		GeoURL:      os.Getenv("GEO_URL"),
+		HTTPClient:  httpClient,
+		Units:       unit,
+		Lang:        envLang(),
+	}
+}
+
+// WithUnits returns a copy of c that asks for unit
+func (c *WeatherClient) WithUnits(unit Unit) Provider {
+	client := *c
+	client.Units = unit
+	return &client
+}
+
+// Fetch is GetCurrent, to satisfy Provider
+func (c *WeatherClient) Fetch(ctx context.Context, city string) (WeatherData, error) {
+	return c.GetCurrent(ctx, city)
+}
+
+func (c *WeatherClient) httpClient() *http.Client {
+	if c.HTTPClient == nil {
+		return http.DefaultClient
+	}
+	return c.HTTPClient
+}
+
+// params holds the parameters every request carries
+func (c *WeatherClient) params() url.Values {
+	params := url.Values{}
+	params.Set("appid", c.APIKey)
+	params.Set("units", c.Units.APIParam())
+	if c.Lang != "" {
+		params.Set("lang", c.Lang)
+	}
+	return params
+}
+
+// query builds the query shared by weather and forecast requests. The input
+// is trimmed and sent as id=, lat= and lon=, or q=; Encode escapes spaces and
+// the like.
+func (c *WeatherClient) query(input string) url.Values {
+	params := c.params()
+
+	input = strings.TrimSpace(input)
+	if id, ok := parseCityID(input); ok {
+		params.Set("id", id)
+	} else if lat, lon, err := parseCoords(input); err == nil {
+		setCoords(params, lat, lon)
+	} else {
+		params.Set("q", input)
+	}
+	return params
+}
+
+func setCoords(params url.Values, lat, lon float64) {
+	params.Set("lat", strconv.FormatFloat(lat, 'f', -1, 64))
+	params.Set("lon", strconv.FormatFloat(lon, 'f', -1, 64))
+}
+
+// GetCurrent fetches the weather for a city name, "id:" query or "lat,lon"
+// pair. Coordinates go to GetCurrentByCoords, so out of range values get a
+// clear error rather than a city search.
+func (c *WeatherClient) GetCurrent(ctx context.Context, city string) (WeatherData, error) {
+	if strings.TrimSpace(city) == "" {
+		return WeatherData{}, ErrEmptyQuery
+	}
+	if isCoords(city) {
+		lat, lon, err := parseCoords(city)
+		if err != nil {
+			return WeatherData{}, err
+		}
+		return c.GetCurrentByCoords(ctx, lat, lon)
+	}
+	return c.getCurrent(ctx, c.query(city), city)
+}
+
+// GetCurrentByCoords looks up the weather at a point. Location comes from the
+// API's name for the nearest place, or the coordinates if it has none.
+func (c *WeatherClient) GetCurrentByCoords(ctx context.Context, lat, lon float64) (WeatherData, error) {
+	params := c.params()
+	setCoords(params, lat, lon)
+	return c.getCurrent(ctx, params, formatCoords(lat, lon))
+}
+
+// getCurrent requests the current weather for params. label names the
+// location when the response has no name.
+func (c *WeatherClient) getCurrent(ctx context.Context, params url.Values, label string) (WeatherData, error) {
+	var weather WeatherData
+
+	requestURL, err := withQuery(c.BaseURL, params)
+	if err != nil {
+		return weather, err
+	}
+
+	resp, err := retryGet(ctx, c.httpClient(), requestURL)
+	if err != nil {
+		return weather, requestError("fetch weather", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		recordRawResponse(body)
+		switch resp.StatusCode {
+		case http.StatusNotFound:
+			return weather, ErrCityNotFound
+		case http.StatusTooManyRequests:
+			return weather, rateLimitError(resp, time.Now())
+		}
+		return weather, fmt.Errorf("API error (%d): %s", resp.StatusCode, string(body))
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return weather, requestError("read response", err)
+	}
+	recordRawResponse(body)
+
+	// API_URL WITH mode=xml RETURNS XML, WHICH WOULD OTHERWISE FAIL AS BAD JSON
+	if bytes.HasPrefix(bytes.TrimSpace(body), []byte("<")) {
+		return weather, fmt.Errorf("API returned XML; set mode=json or remove mode param")
+	}
+
+	var apiResp OpenWeatherResponse
+	if err := decodeResponse(body, &apiResp); err != nil {
+		return weather, err
+	}
+
+	weather = apiResp.toWeatherData(label)
+	weather.Unit = c.Units
+	weather.FetchedAt = time.Now()
+
+	return weather, nil
+}
+
+// toWeatherData maps the response onto WeatherData. query labels the
+// location when the response has no name, as coordinate lookups can.
+func (r OpenWeatherResponse) toWeatherData(query string) WeatherData {
+	weather := WeatherData{
+		Location:    r.Name,
+		Temperature: int(r.Main.Temp),
+		FeelsLike:   int(r.Main.FeelsLike),
+		Humidity:    int(r.Main.Humidity),
+		WindSpeed:   float32(r.Wind.Speed),
+		Pressure:    int(r.Main.Pressure),
+		Visibility:  VISIBILITY_UNKNOWN,
+		WindDeg:     WIND_DEG_UNKNOWN,
+	}
+	visibility := r.Visibility
+	windDeg := r.Wind.Deg
+	conditions := r.Weather
+	sunrise, sunset := r.Sys.Sunrise, r.Sys.Sunset
+
+	if offset, ok := r.Timezone.(float64); ok {
+		weather.TimezoneOffset = int(offset)
+	}
+	if r.Main.TempMin != nil && r.Main.TempMax != nil {
+		weather.TempMin, weather.TempMax = int(*r.Main.TempMin), int(*r.Main.TempMax)
+		weather.HasTempRange = true
+	}
+
+	if r.Current != nil {
+		weather.Temperature = int(r.Current.Temp)
+		weather.FeelsLike = int(r.Current.FeelsLike)
+		weather.Humidity = int(r.Current.Humidity)
+		weather.WindSpeed = float32(r.Current.WindSpeed)
+		weather.Pressure = int(r.Current.Pressure)
+		visibility = r.Current.Visibility
+		windDeg = r.Current.WindDeg
+		weather.TimezoneOffset = r.TimezoneOffset
+		conditions = r.Current.Weather
+		sunrise, sunset = r.Current.Sunrise, r.Current.Sunset
+
+		weather.HasTempRange = len(r.Daily) > 0
+		if weather.HasTempRange {
+			weather.TempMin, weather.TempMax = int(r.Daily[0].Temp.Min), int(r.Daily[0].Temp.Max)
+		}
+	}
+
+	if visibility != nil {
+		weather.Visibility = *visibility
+	}
+	if windDeg != nil {
+		weather.WindDeg = (*windDeg%360 + 360) % 360
+	}
+
+	weather.Sunrise = unixLocalTime(sunrise, weather.TimezoneOffset)
+	weather.Sunset = unixLocalTime(sunset, weather.TimezoneOffset)
+
+	if len(conditions) > 0 {
+		weather.Condition = conditions[0].Main
+		weather.ConditionID = conditions[0].ID
+		weather.Description = conditions[0].Description
+	}
+
+	if weather.Location == "" {
+		weather.Location = query
+	}
+
+	return weather
+}
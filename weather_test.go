@@ -0,0 +1,382 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testClient points a WeatherClient at server
+func testClient(server *httptest.Server) *WeatherClient {
+	return &WeatherClient{APIKey: "test-key", BaseURL: server.URL, HTTPClient: server.Client()}
+}
+
+// serveBody starts a server answering every request with status and body
+func serveBody(t *testing.T, status int, body string) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// decodeFixture decodes a response body the way getCurrent does
+func decodeFixture(t *testing.T, body string) OpenWeatherResponse {
+	t.Helper()
+
+	var resp OpenWeatherResponse
+	if err := json.Unmarshal([]byte(body), &resp); err != nil {
+		t.Fatalf("fixture does not decode: %v", err)
+	}
+	return resp
+}
+
+func TestToWeatherDataNamelessUsesQuery(t *testing.T) {
+	resp := decodeFixture(t, `{"main": {"temp": 12}, "weather": [{"id": 800, "main": "Clear"}]}`)
+
+	weather := resp.toWeatherData("51.5,-0.13")
+	if weather.Location != "51.5,-0.13" {
+		t.Errorf("Location = %q, want the query", weather.Location)
+	}
+
+	named := decodeFixture(t, `{"name": "London", "main": {"temp": 12}}`).toWeatherData("51.5,-0.13")
+	if named.Location != "London" {
+		t.Errorf("Location = %q, want the API name", named.Location)
+	}
+}
+
+func TestGetCurrentRejectsXML(t *testing.T) {
+	server := serveBody(t, http.StatusOK, `<?xml version="1.0"?><current><city name="London"/></current>`)
+
+	_, err := testClient(server).GetCurrent(context.Background(), "London")
+	if err == nil || !strings.Contains(err.Error(), "API returned XML") {
+		t.Fatalf("err = %v, want the XML error", err)
+	}
+}
+
+// TestGetCurrentModeXML reproduces the misconfiguration itself: mode=xml in
+// API_URL must reach the server, which then answers in XML, alongside appid
+func TestGetCurrentModeXML(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("appid") != "test-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"cod": 401, "message": "Invalid API key"}`))
+			return
+		}
+		if query.Get("mode") == "xml" {
+			w.Write([]byte(`<?xml version="1.0"?><current><city name="London"/></current>`))
+			return
+		}
+		w.Write([]byte(`{"name": "London"}`))
+	}))
+	defer server.Close()
+
+	t.Setenv("API_KEY", "test-key")
+	t.Setenv("API_URL", server.URL+"/data/2.5/weather?mode=xml")
+	client := newWeatherClient(Celsius)
+	client.HTTPClient = server.Client()
+
+	_, err := client.GetCurrent(context.Background(), "London")
+	if err == nil || !strings.Contains(err.Error(), "API returned XML") {
+		t.Fatalf("err = %v, want the XML error", err)
+	}
+}
+
+func TestToWeatherDataTiers(t *testing.T) {
+	free := decodeFixture(t, `{
+		"name": "Berlin",
+		"timezone": 7200,
+		"main": {"temp": 21.6, "feels_like": 20.9, "temp_min": 19.2, "temp_max": 23.8, "humidity": 55, "pressure": 1016},
+		"wind": {"speed": 3.2, "deg": 250},
+		"weather": [{"id": 801, "main": "Clouds", "description": "few clouds"}]
+	}`).toWeatherData("Berlin")
+
+	oneCall := decodeFixture(t, `{
+		"timezone": "Europe/Berlin",
+		"timezone_offset": 7200,
+		"current": {
+			"temp": 21.6, "feels_like": 20.9, "humidity": 55, "pressure": 1016,
+			"wind_speed": 3.2, "wind_deg": 250,
+			"weather": [{"id": 801, "main": "Clouds", "description": "few clouds"}]
+		},
+		"daily": [{"temp": {"min": 14.1, "max": 24.7}}, {"temp": {"min": 15, "max": 26}}]
+	}`).toWeatherData("Berlin")
+
+	tests := []struct {
+		name             string
+		weather          WeatherData
+		tempMin, tempMax int
+	}{
+		{"free", free, 19, 23},
+		{"one call", oneCall, 14, 24},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w := test.weather
+			if w.Location != "Berlin" || w.TimezoneOffset != 7200 {
+				t.Errorf("Location, TimezoneOffset = %q, %d; want Berlin, 7200", w.Location, w.TimezoneOffset)
+			}
+			if w.Temperature != 21 || w.FeelsLike != 20 || w.Humidity != 55 || w.Pressure != 1016 {
+				t.Errorf("current reading = %+v", w)
+			}
+			if w.WindSpeed != 3.2 || w.WindDeg != 250 {
+				t.Errorf("wind = %v from %d, want 3.2 from 250", w.WindSpeed, w.WindDeg)
+			}
+			if w.Condition != "Clouds" || w.ConditionID != 801 || w.Description != "few clouds" {
+				t.Errorf("condition = %q %d %q", w.Condition, w.ConditionID, w.Description)
+			}
+			if !w.HasTempRange || w.TempMin != test.tempMin || w.TempMax != test.tempMax {
+				t.Errorf("range = %d..%d (%v), want %d..%d", w.TempMin, w.TempMax, w.HasTempRange, test.tempMin, test.tempMax)
+			}
+		})
+	}
+}
+
+func TestToWeatherDataTempRange(t *testing.T) {
+	tests := []struct {
+		name             string
+		body             string
+		has              bool
+		tempMin, tempMax int
+	}{
+		{"free with range", `{"main": {"temp": 5, "temp_min": -2.5, "temp_max": 7.9}}`, true, -2, 7},
+		{"free at zero", `{"main": {"temp": 0, "temp_min": 0, "temp_max": 0}}`, true, 0, 0},
+		{"free without range", `{"main": {"temp": 5}}`, false, 0, 0},
+		{"free with min only", `{"main": {"temp": 5, "temp_min": 1}}`, false, 0, 0},
+		{"one call without daily", `{"current": {"temp": 5}}`, false, 0, 0},
+		{"one call with empty daily", `{"current": {"temp": 5}, "daily": []}`, false, 0, 0},
+		{"one call with daily", `{"current": {"temp": 5}, "daily": [{"temp": {"min": 1.2, "max": 9.8}}]}`, true, 1, 9},
+	}
+	for _, test := range tests {
+		w := decodeFixture(t, test.body).toWeatherData("Oslo")
+		if w.HasTempRange != test.has {
+			t.Errorf("%s: HasTempRange = %v, want %v", test.name, w.HasTempRange, test.has)
+			continue
+		}
+		if test.has && (w.TempMin != test.tempMin || w.TempMax != test.tempMax) {
+			t.Errorf("%s: range = %d..%d, want %d..%d", test.name, w.TempMin, w.TempMax, test.tempMin, test.tempMax)
+		}
+	}
+}
+
+func TestToWeatherDataMissingWind(t *testing.T) {
+	server := serveBody(t, http.StatusOK, `{"name": "Lima", "main": {"temp": 18, "humidity": 80}}`)
+
+	weather, err := testClient(server).GetCurrent(context.Background(), "Lima")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if weather.WindSpeed != 0 || weather.WindDeg != WIND_DEG_UNKNOWN {
+		t.Errorf("wind = %v from %d, want 0 from WIND_DEG_UNKNOWN", weather.WindSpeed, weather.WindDeg)
+	}
+	if weather.Temperature != 18 || weather.Humidity != 80 {
+		t.Errorf("reading = %+v, want the main block kept", weather)
+	}
+}
+
+func TestUnixLocalTime(t *testing.T) {
+	// 2024-06-01 12:00:00 UTC
+	const NOON_UTC int64 = 1717243200
+
+	tests := []struct {
+		name         string
+		offset       int
+		hour, minute int
+		day          int
+	}{
+		{"UTC", 0, 12, 0, 1},
+		{"Berlin summer", 2 * 3600, 14, 0, 1},
+		{"New York summer", -4 * 3600, 8, 0, 1},
+		{"India half hour", 5*3600 + 1800, 17, 30, 1},
+		{"Kiritimati", 14 * 3600, 2, 0, 2},
+		{"Baker Island", -12 * 3600, 0, 0, 1},
+	}
+	for _, test := range tests {
+		local := unixLocalTime(NOON_UTC, test.offset)
+		if local.Hour() != test.hour || local.Minute() != test.minute || local.Day() != test.day {
+			t.Errorf("%s: %v, want day %d %02d:%02d", test.name, local, test.day, test.hour, test.minute)
+		}
+		if _, offset := local.Zone(); offset != test.offset {
+			t.Errorf("%s: zone offset %d, want %d", test.name, offset, test.offset)
+		}
+		if !local.Equal(time.Unix(NOON_UTC, 0)) {
+			t.Errorf("%s: %v is not the same instant", test.name, local)
+		}
+	}
+
+	if !unixLocalTime(0, 3600).IsZero() {
+		t.Error("a missing timestamp should give the zero time")
+	}
+}
+
+func TestGetCurrentNotFound(t *testing.T) {
+	server := serveBody(t, http.StatusNotFound, `{"cod": "404", "message": "city not found"}`)
+
+	_, err := testClient(server).GetCurrent(context.Background(), "Atlantis")
+	if !errors.Is(err, ErrCityNotFound) {
+		t.Fatalf("err = %v, want ErrCityNotFound", err)
+	}
+}
+
+func TestWeatherClientQuery(t *testing.T) {
+	client := &WeatherClient{APIKey: "key"}
+
+	tests := []struct {
+		input, want string
+	}{
+		{"New York, US", "q=New+York%2C+US"},
+		{"  São Paulo ", "q=S%C3%A3o+Paulo"},
+		{"id:2643743", "id=2643743"},
+		{"51.5, -0.13", "lat=51.5&lon=-0.13"},
+	}
+	for _, test := range tests {
+		if encoded := client.query(test.input).Encode(); !strings.Contains(encoded, test.want) {
+			t.Errorf("query(%q) = %s, want it to contain %s", test.input, encoded, test.want)
+		}
+	}
+}
+
+func TestGetCurrentSendsEncodedQuery(t *testing.T) {
+	var rawQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rawQuery = r.URL.RawQuery
+		w.Write([]byte(`{"name": "New York"}`))
+	}))
+	defer server.Close()
+
+	if _, err := testClient(server).GetCurrent(context.Background(), "New York, US"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(rawQuery, "q=New+York%2C+US") {
+		t.Errorf("server got %q, want q=New+York%%2C+US", rawQuery)
+	}
+}
+
+func TestGetCurrentKeepsBaseURLQuery(t *testing.T) {
+	var got url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.URL.Query()
+		w.Write([]byte(`{"name": "Oslo"}`))
+	}))
+	defer server.Close()
+
+	client := testClient(server)
+	client.BaseURL = server.URL + "/data/2.5/weather?mode=json&cnt=1"
+	if _, err := client.GetCurrent(context.Background(), "Oslo"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{"mode": "json", "cnt": "1", "appid": "test-key", "q": "Oslo"}
+	for key, value := range want {
+		if got.Get(key) != value {
+			t.Errorf("%s = %q, want %q (query %v)", key, got.Get(key), value, got)
+		}
+	}
+}
+
+func TestGetCurrentRateLimited(t *testing.T) {
+	tests := []struct {
+		name, retryAfter string
+		want             time.Duration
+	}{
+		{"seconds", "120", 2 * time.Minute},
+		{"http date", time.Now().Add(90 * time.Second).UTC().Format(http.TimeFormat), 90 * time.Second},
+		{"missing", "", RATE_LIMIT_WAIT_DEFAULT},
+		{"garbage", "soon", RATE_LIMIT_WAIT_DEFAULT},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if test.retryAfter != "" {
+					w.Header().Set("Retry-After", test.retryAfter)
+				}
+				w.WriteHeader(http.StatusTooManyRequests)
+			}))
+			defer server.Close()
+
+			_, err := testClient(server).GetCurrent(context.Background(), "London")
+			var rateLimited *RateLimitError
+			if !errors.As(err, &rateLimited) {
+				t.Fatalf("err = %v, want a RateLimitError", err)
+			}
+			// HTTP DATES HAVE WHOLE SECONDS, SO ALLOW FOR THE ROUNDING
+			if wait := rateLimited.Wait(); wait < test.want-2*time.Second || wait > test.want {
+				t.Errorf("Wait = %v, want %v", wait, test.want)
+			}
+		})
+	}
+}
+
+func TestGetCurrentLang(t *testing.T) {
+	var lang string
+	var langSet bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lang, langSet = r.URL.Query().Get("lang"), r.URL.Query().Has("lang")
+		w.Write([]byte(`{"name": "Berlin", "weather": [{"id": 500, "main": "Rain", "description": "leichter Regen"}]}`))
+	}))
+	defer server.Close()
+
+	client := testClient(server)
+	client.Lang = "de"
+	weather, err := client.GetCurrent(context.Background(), "Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lang != "de" {
+		t.Errorf("lang = %q, want de", lang)
+	}
+	if weather.Description != "leichter Regen" || weather.Condition != "Rain" {
+		t.Errorf("Description, Condition = %q, %q; want the translated text and the English main", weather.Description, weather.Condition)
+	}
+
+	client.Lang = ""
+	if _, err := client.GetCurrent(context.Background(), "Berlin"); err != nil {
+		t.Fatal(err)
+	}
+	if langSet {
+		t.Errorf("lang=%q sent without a language set", lang)
+	}
+}
+
+func TestDecodeResponse(t *testing.T) {
+	tests := []struct {
+		name, body string
+		want       error
+	}{
+		{"empty", "", ErrEmptyResponse},
+		{"whitespace", " \n\t", ErrEmptyResponse},
+		{"null", "null", ErrEmptyResponse},
+		{"padded null", "  null\n", ErrEmptyResponse},
+		{"truncated", `{"name": "Par`, ErrMalformedResponse},
+		{"cut after key", `{"main": {"temp":`, ErrMalformedResponse},
+		{"array", `[{"name": "Paris"}]`, ErrMalformedResponse},
+		{"string", `"Paris"`, ErrMalformedResponse},
+		{"wrong field type", `{"main": {"temp": "warm"}}`, ErrMalformedResponse},
+		{"html error page", `<html>502 Bad Gateway</html>`, ErrMalformedResponse},
+		{"empty object", `{}`, nil},
+		{"unknown fields", `{"name": "Paris", "extra": [1, 2]}`, nil},
+	}
+	for _, test := range tests {
+		var resp OpenWeatherResponse
+		err := decodeResponse([]byte(test.body), &resp)
+		if test.want == nil {
+			if err != nil {
+				t.Errorf("%s: err = %v, want nil", test.name, err)
+			}
+			continue
+		}
+		if !errors.Is(err, test.want) {
+			t.Errorf("%s: err = %v, want %v", test.name, err, test.want)
+		}
+	}
+}
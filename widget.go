@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+const (
+	WIDGET_WIDTH  int32 = 220
+	WIDGET_HEIGHT int32 = 110
+)
+
+// isWidgetMode reports whether the WIDGET_MODE config flag is set
+func isWidgetMode() bool {
+	enabled, _ := strconv.ParseBool(os.Getenv("WIDGET_MODE"))
+	return enabled
+}
+
+// STORM_SYMBOL is the one weather symbol JetBrains Mono has, so fontRanges
+// loads it
+const STORM_SYMBOL rune = '⚡'
+
+// conditionSymbol returns a short glyph for the condition. JetBrains Mono has
+// no other weather symbols, so other conditions, and a storm in a font
+// without the symbol, fall back to their label.
+func conditionSymbol(condition string, hasGlyph func(r rune) bool) string {
+	if condition == "Thunderstorm" && hasGlyph(STORM_SYMBOL) {
+		return string(STORM_SYMBOL)
+	}
+	return conditionLabel(condition)
+}
+
+// drawWidget draws the minimal pinned widget: temperature and condition only
+func drawWidget(font rl.Font, theme Theme, weather WeatherData) {
+	if weather.Location == "" {
+		rl.DrawTextEx(font, "--"+degreeSign, rl.NewVector2(20, 20), 56, 0, theme.Muted)
+		return
+	}
+
+	rl.DrawTextEx(
+		font,
+		fmt.Sprintf("%d%s", weather.Temperature, degreeSign),
+		rl.NewVector2(20, 20), 56, 0, theme.Strong,
+	)
+
+	rl.DrawTextEx(
+		font,
+		conditionSymbol(weather.Condition, func(r rune) bool { return fontHasGlyph(font, r) }),
+		rl.NewVector2(130, 40), 20, 0, theme.Text,
+	)
+}
+
+// dragWidget moves the borderless widget window while the left button is held.
+// The window follows the mouse, so the grab point stays under the cursor.
+func dragWidget(anchor *rl.Vector2) {
+	if rl.IsMouseButtonPressed(rl.MouseLeftButton) {
+		*anchor = rl.GetMousePosition()
+	}
+
+	if rl.IsMouseButtonDown(rl.MouseLeftButton) {
+		mouse := rl.GetMousePosition()
+		position := rl.GetWindowPosition()
+		rl.SetWindowPosition(
+			int(position.X+mouse.X-anchor.X),
+			int(position.Y+mouse.Y-anchor.Y),
+		)
+	}
+}
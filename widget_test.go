@@ -0,0 +1,35 @@
+package main
+
+import "testing"
+
+func TestConditionSymbol(t *testing.T) {
+	allGlyphs := func(r rune) bool { return true }
+	noStorm := func(r rune) bool { return r != STORM_SYMBOL }
+
+	tests := []struct {
+		condition string
+		hasGlyph  func(r rune) bool
+		want      string
+	}{
+		{"Thunderstorm", allGlyphs, "⚡"},
+		{"Thunderstorm", noStorm, conditionLabel("Thunderstorm")},
+		{"Rain", allGlyphs, conditionLabel("Rain")},
+		{"", allGlyphs, conditionLabel("")},
+	}
+	for _, test := range tests {
+		if got := conditionSymbol(test.condition, test.hasGlyph); got != test.want {
+			t.Errorf("conditionSymbol(%q) = %q, want %q", test.condition, got, test.want)
+		}
+	}
+}
+
+func TestConditionSymbolsLoaded(t *testing.T) {
+	for _, condition := range []string{"Thunderstorm", "Rain", "Snow", "Clear", "Clouds", "Mist"} {
+		symbol := conditionSymbol(condition, func(r rune) bool { return true })
+		for _, r := range symbol {
+			if !inFontRanges(r) {
+				t.Errorf("%s: symbol %q has %U, outside fontRanges", condition, symbol, r)
+			}
+		}
+	}
+}
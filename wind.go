@@ -0,0 +1,50 @@
+package main
+
+import (
+	"math"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// WIND_DEG_UNKNOWN MARKS A RESPONSE WITHOUT A WIND DIRECTION
+const WIND_DEG_UNKNOWN int = -1
+
+var cardinals = []string{"N", "NE", "E", "SE", "S", "SW", "W", "NW"}
+
+// windCardinal names the compass point nearest degrees, e.g. 30 gives "NE".
+// An unknown direction gives "".
+func windCardinal(degrees int) string {
+	if degrees == WIND_DEG_UNKNOWN {
+		return ""
+	}
+	sector := 360.0 / float64(len(cardinals))
+	index := int(math.Round(float64(degrees)/sector)) % len(cardinals)
+	return cardinals[index]
+}
+
+// drawWindArrow draws a compass ring with an arrow showing where the wind
+// blows to, the opposite of the "from" bearing the API reports. origin is the
+// top left of a size by size box. Unknown directions draw nothing.
+func drawWindArrow(degrees int, origin rl.Vector2, size float32, color rl.Color) {
+	if degrees == WIND_DEG_UNKNOWN {
+		return
+	}
+
+	radius := size / 2
+	center := rl.NewVector2(origin.X+radius, origin.Y+radius)
+	rl.DrawCircleLinesV(center, radius, color)
+
+	// SCREEN ANGLES RUN CLOCKWISE FROM UP, LIKE COMPASS BEARINGS
+	angle := float64(degrees+180) * math.Pi / 180
+	dir := rl.NewVector2(float32(math.Sin(angle)), float32(-math.Cos(angle)))
+	side := rl.NewVector2(-dir.Y, dir.X)
+
+	tip := rl.Vector2Add(center, rl.Vector2Scale(dir, radius*0.8))
+	base := rl.Vector2Subtract(center, rl.Vector2Scale(dir, radius*0.6))
+	rl.DrawTriangle(
+		tip,
+		rl.Vector2Subtract(base, rl.Vector2Scale(side, radius*0.5)),
+		rl.Vector2Add(base, rl.Vector2Scale(side, radius*0.5)),
+		color,
+	)
+}
@@ -0,0 +1,31 @@
+package main
+
+import "testing"
+
+func TestWindCardinal(t *testing.T) {
+	tests := []struct {
+		degrees int
+		want    string
+	}{
+		{0, "N"},
+		{22, "N"},
+		{23, "NE"},
+		{45, "NE"},
+		{90, "E"},
+		{135, "SE"},
+		{180, "S"},
+		{225, "SW"},
+		{270, "W"},
+		{315, "NW"},
+		{337, "NW"},
+		{338, "N"},
+		{359, "N"},
+		{360, "N"},
+		{WIND_DEG_UNKNOWN, ""},
+	}
+	for _, test := range tests {
+		if got := windCardinal(test.degrees); got != test.want {
+			t.Errorf("windCardinal(%d) = %q, want %q", test.degrees, got, test.want)
+		}
+	}
+}